@@ -0,0 +1,153 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"errors"
+	"math/big"
+	"time"
+)
+
+// ErrInvalidPeriod ...
+var ErrInvalidPeriod = errors.New("invalid period")
+
+// Accrual ...
+type Accrual struct {
+	Profit Amount // gross profit
+	Fee    Amount // part of Profit paid to the structure fee address
+	Net    Amount // Profit minus Fee, credited to the balance owner
+}
+
+// AccrualCalculator computes simple (non-compounding) profit: a balance earns
+// ProfitPercent once per Period, pro rata for partial periods. Profit is
+// rounded down, Fee is FeePercent of Profit rounded down, Net gets the rest.
+type AccrualCalculator struct {
+	Period time.Duration
+}
+
+// StructureUpdate ...
+type StructureUpdate struct {
+	Time   time.Time
+	Profit Percent
+	Fee    Percent
+}
+
+// NewAccrualCalculator ...
+func NewAccrualCalculator(period time.Duration) *AccrualCalculator {
+	return &AccrualCalculator{Period: period}
+}
+
+// NewStructureUpdate ...
+func NewStructureUpdate(t Transaction, at time.Time) (StructureUpdate, error) {
+	if err := assert(t, lengthIs(TxLength)); err != nil {
+		return StructureUpdate{}, err
+	}
+
+	if v := t.Version(); v != CreateStructure && v != UpdateStructure {
+		return StructureUpdate{}, ErrInvalidVersion
+	}
+
+	return StructureUpdate{Time: at, Profit: t.Profit(), Fee: t.Fee()}, nil
+}
+
+// Accrue ...
+func (c *AccrualCalculator) Accrue(balance Amount, profit, fee Percent, span time.Duration) (Accrual, error) {
+	if c.Period <= 0 || span < 0 {
+		return Accrual{}, ErrInvalidPeriod
+	}
+
+	n := new(big.Int).SetUint64(uint64(balance))
+	n.Mul(n, big.NewInt(int64(profit)))
+	n.Mul(n, big.NewInt(int64(span)))
+
+	d := big.NewInt(percentScale)
+	d.Mul(d, big.NewInt(int64(c.Period)))
+
+	n.Quo(n, d)
+
+	if !n.IsUint64() {
+		return Accrual{}, ErrAmountOverflow
+	}
+
+	return split(Amount(n.Uint64()), fee)
+}
+
+// Replay sums accruals over consecutive intervals between updates, each
+// interval uses the percents of the update that starts it and is rounded
+// separately. Updates must be sorted by time, the last interval ends at until.
+func (c *AccrualCalculator) Replay(balance Amount, updates []StructureUpdate, until time.Time) (sum Accrual, err error) {
+	for i := 1; i < len(updates); i++ {
+		if updates[i].Time.Before(updates[i-1].Time) {
+			return Accrual{}, ErrInvalidPeriod
+		}
+	}
+
+	for i, u := range updates {
+		if !u.Time.Before(until) {
+			break
+		}
+
+		end := until
+
+		if i+1 < len(updates) && updates[i+1].Time.Before(until) {
+			end = updates[i+1].Time
+		}
+
+		a, err := c.Accrue(balance, u.Profit, u.Fee, end.Sub(u.Time))
+		if err != nil {
+			return Accrual{}, err
+		}
+
+		if sum, err = sum.add(a); err != nil {
+			return Accrual{}, err
+		}
+	}
+
+	return sum, nil
+}
+
+func split(profit Amount, fee Percent) (Accrual, error) {
+	f, err := fee.Of(profit)
+	if err != nil {
+		return Accrual{}, err
+	}
+
+	net, err := profit.Sub(f)
+	if err != nil {
+		return Accrual{}, err
+	}
+
+	return Accrual{Profit: profit, Fee: f, Net: net}, nil
+}
+
+func (a Accrual) add(b Accrual) (r Accrual, err error) {
+	if r.Profit, err = a.Profit.Add(b.Profit); err != nil {
+		return r, err
+	}
+
+	if r.Fee, err = a.Fee.Add(b.Fee); err != nil {
+		return r, err
+	}
+
+	r.Net, err = a.Net.Add(b.Net)
+
+	return r, err
+}
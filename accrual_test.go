@@ -0,0 +1,117 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/umitop/libumi"
+)
+
+const day = 24 * time.Hour
+
+func TestAccrualCalculator_Accrue(t *testing.T) {
+	c := libumi.NewAccrualCalculator(30 * day)
+
+	tests := []struct {
+		balance libumi.Amount
+		profit  libumi.Percent
+		fee     libumi.Percent
+		span    time.Duration
+		exp     libumi.Accrual
+	}{
+		{1000_00, 5_00, 20_00, 30 * day, libumi.Accrual{Profit: 50_00, Fee: 10_00, Net: 40_00}},
+		{1000_00, 5_00, 20_00, 15 * day, libumi.Accrual{Profit: 25_00, Fee: 5_00, Net: 20_00}},
+		{1000_00, 5_00, 0, 60 * day, libumi.Accrual{Profit: 100_00, Fee: 0, Net: 100_00}},
+		{1_00, 1_00, 20_00, 29 * day, libumi.Accrual{Profit: 0, Fee: 0, Net: 0}},
+		{3_33, 3_33, 33_33, 30 * day, libumi.Accrual{Profit: 11, Fee: 3, Net: 8}},
+		{1000_00, 5_00, 20_00, 0, libumi.Accrual{}},
+	}
+
+	for _, test := range tests {
+		act, err := c.Accrue(test.balance, test.profit, test.fee, test.span)
+		if err != nil {
+			t.Fatalf("Expected: nil, got: %v", err)
+		}
+
+		if act != test.exp {
+			t.Fatalf("Expected: %+v, got: %+v", test.exp, act)
+		}
+	}
+}
+
+func TestAccrualCalculator_AccrueError(t *testing.T) {
+	if _, err := libumi.NewAccrualCalculator(0).Accrue(1, 1, 1, day); !errors.Is(err, libumi.ErrInvalidPeriod) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidPeriod, err)
+	}
+
+	if _, err := libumi.NewAccrualCalculator(day).Accrue(1, 1, 1, -day); !errors.Is(err, libumi.ErrInvalidPeriod) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidPeriod, err)
+	}
+
+	_, err := libumi.NewAccrualCalculator(time.Nanosecond).Accrue(1<<62, 5_00, 0, 1<<40)
+	if !errors.Is(err, libumi.ErrAmountOverflow) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrAmountOverflow, err)
+	}
+}
+
+func TestAccrualCalculator_Replay(t *testing.T) {
+	c := libumi.NewAccrualCalculator(30 * day)
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	create := newTxStruct().SetProfit(5_00).SetFee(10_00)
+	update := newTxStruct().SetVersion(libumi.UpdateStructure).SetProfit(2_00).SetFee(50_00)
+
+	u1, err := libumi.NewStructureUpdate(create, t0)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	u2, _ := libumi.NewStructureUpdate(update, t0.Add(30*day))
+	updates := []libumi.StructureUpdate{u1, u2}
+
+	act, err := c.Replay(1000_00, updates, t0.Add(45*day))
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	exp := libumi.Accrual{Profit: 50_00 + 10_00, Fee: 5_00 + 5_00, Net: 45_00 + 5_00}
+	if act != exp {
+		t.Fatalf("Expected: %+v, got: %+v", exp, act)
+	}
+
+	act, _ = c.Replay(1000_00, updates, t0.Add(15*day))
+	exp = libumi.Accrual{Profit: 25_00, Fee: 2_50, Net: 22_50}
+
+	if act != exp {
+		t.Fatalf("Expected: %+v, got: %+v", exp, act)
+	}
+
+	if _, err = c.Replay(1, []libumi.StructureUpdate{u2, u1}, t0); !errors.Is(err, libumi.ErrInvalidPeriod) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidPeriod, err)
+	}
+
+	if _, err = libumi.NewStructureUpdate(libumi.NewTransaction(), t0); !errors.Is(err, libumi.ErrInvalidVersion) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidVersion, err)
+	}
+}
@@ -36,6 +36,19 @@ const (
 // Address ...
 type Address []byte
 
+// DecodeMode ...
+type DecodeMode uint8
+
+// Address decoding modes. Lenient accepts any letter case, Strict accepts only
+// all lower or all upper case, as BIP-173 does, and reports an *AddressError.
+const (
+	Bech32Lenient DecodeMode = iota
+	Bech32Strict
+)
+
+// AddressKey ...
+type AddressKey [AddressLength]byte
+
 // NewAddress ...
 func NewAddress() Address {
 	adr := make(Address, AddressLength)
@@ -58,6 +71,59 @@ func NewAddressFromBech32(s string) (Address, error) {
 	return adr, nil
 }
 
+// NewAddressFromKey ...
+func NewAddressFromKey(k AddressKey) Address {
+	adr := make(Address, AddressLength)
+	copy(adr, k[:])
+
+	return adr
+}
+
+// Key ...
+func (a Address) Key() (k AddressKey) {
+	copy(k[:], a)
+
+	return k
+}
+
+// Address ...
+func (k AddressKey) Address() Address {
+	return NewAddressFromKey(k)
+}
+
+// NewAddressFromBech32Mode ...
+func NewAddressFromBech32Mode(s string, m DecodeMode) (Address, error) {
+	if m != Bech32Strict {
+		return NewAddressFromBech32(s)
+	}
+
+	adr, err := ParseAddress(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if adr.Bech32() != strings.ToLower(s) {
+		return nil, &AddressError{Err: ErrNonCanonical}
+	}
+
+	return adr, nil
+}
+
+// ParseAddress decodes a bech32 address like NewAddressFromBech32 but rejects
+// mixed case and returns an *AddressError describing what is wrong.
+func ParseAddress(s string) (Address, error) {
+	pfx, pub, err := bech32DecodeVerbose(s)
+	if err != nil {
+		return nil, err
+	}
+
+	adr := NewAddress()
+	adr.SetPrefix(pfx)
+	adr.SetPublicKey(pub)
+
+	return adr, nil
+}
+
 // Bech32 ...
 func (a Address) Bech32() string {
 	return bech32Encode(a.Prefix(), a.PublicKey())
@@ -20,196 +20,134 @@
 
 package libumi
 
-import "strings"
+import (
+	"crypto/ed25519"
+	"errors"
+	"strings"
+
+	"github.com/umitop/libumi/bech32"
+)
 
 const (
 	prefixAbc  = " abcdefghijklmnopqrstuvwxyz"
-	bech32Abc  = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
 	pfxGenesis = "genesis"
 	pfxLen     = 3
-	dataLen    = 59
-	addressLen = 62
+	dataLen    = 58
 )
 
-func bech32Encode(pfx string, pub []byte) string {
-	data := bech32Convert8to5(pub)
-
-	var s strings.Builder
+// ErrNonCanonical ...
+var ErrNonCanonical = errors.New("non-canonical form")
 
-	s.Grow(addressLen)
-	s.WriteString(pfx)
-	s.WriteString("1")
-	s.Write(data)
-	s.Write(bech32CreateChecksum(pfx, data))
-
-	return s.String()
+// AddressError ...
+type AddressError struct {
+	Err       error
+	Positions []int
 }
 
-func bech32Decode(bech string) (pfx string, data []byte, err error) {
-	bech = strings.ToLower(bech)
-
-	if pfx, err = bech32ParsePrefix(bech); err != nil {
-		return
-	}
-
-	if data, err = bech32Convert5to8([]byte(bech[len(pfx)+1 : len(bech)-6])); err != nil {
-		return
-	}
-
-	if !bech32VerifyChecksum(pfx, []byte(bech[len(pfx)+1:])) {
-		err = ErrInvalidAddress
-	}
-
-	return pfx, data, err
+// Error ...
+func (e *AddressError) Error() string {
+	return ErrInvalidAddress.Error() + ": " + (&bech32.Error{Err: e.Err, Positions: e.Positions}).Error()
 }
 
-func bech32ParsePrefix(s string) (string, error) {
-	sep := strings.LastIndexByte(s, '1')
-	if sep == -1 {
-		return "", ErrInvalidAddress
-	}
-
-	if len(s)-sep != dataLen {
-		return "", ErrInvalidAddress
-	}
+// Unwrap ...
+func (e *AddressError) Unwrap() error {
+	return e.Err
+}
 
-	pfx := s[0:sep]
+// Is ...
+func (e *AddressError) Is(target error) bool {
+	return target == ErrInvalidAddress
+}
 
-	if !bech32VerifyPrefix(pfx) {
-		return "", ErrInvalidAddress
-	}
+// bech32Encode does not check the prefix. A version with a 5-bit group of 31
+// maps to the prefix byte 0x7f, such addresses are invalid but are still
+// encoded so that they can be displayed.
+func bech32Encode(pfx string, pub []byte) string {
+	s, _ := bech32.EncodeBytesUnchecked(pfx, pub, bech32.Bech32) // fails only for an unknown encoding
 
-	return pfx, nil
+	return s
 }
 
-func bech32VerifyPrefix(s string) bool {
-	if s == pfxGenesis {
-		return true
+func bech32Decode(s string) (pfx string, pub []byte, err error) {
+	pfx, pub, enc, err := bech32.DecodeBytes(strings.ToLower(s))
+	if err != nil || enc != bech32.Bech32 || len(pub) != ed25519.PublicKeySize || !bech32VerifyPrefix(pfx) {
+		return "", nil, ErrInvalidAddress
 	}
 
-	if len(s) != pfxLen {
-		return false
-	}
+	return pfx, pub, nil
+}
 
-	for i := range s {
-		if strings.IndexByte(prefixAbc, s[i]) < 1 {
-			return false
+func bech32DecodeVerbose(s string) (string, []byte, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 33 || s[i] > 126 {
+			return "", nil, &AddressError{Err: bech32.ErrInvalidCharacter, Positions: []int{i}}
 		}
 	}
 
-	return true
-}
-
-func bech32Convert5to8(data []byte) ([]byte, error) {
-	var acc, bits int
-
-	out := make([]byte, 0, 32)
+	sep := strings.LastIndexByte(s, '1')
 
-	for _, b := range data {
-		v := strings.IndexByte(bech32Abc, b)
-		if v == -1 {
-			return nil, ErrInvalidAddress
+	if pos := len(s) - dataLen - 1; sep != pos {
+		if sep == -1 && pos > 0 {
+			return "", nil, &AddressError{Err: bech32.ErrInvalidSeparator, Positions: []int{pos}}
 		}
 
-		acc = (acc << 5) | v
-		bits += 5
-
-		for bits >= 8 {
-			bits -= 8
-			out = append(out, byte(acc>>bits))
+		if sep == -1 {
+			return "", nil, &AddressError{Err: bech32.ErrInvalidSeparator}
 		}
-	}
-
-	return out, bech32Convert5to8Verify(acc, bits)
-}
 
-func bech32Convert5to8Verify(acc, bits int) error {
-	if bits >= 5 || (acc<<(8-bits))&0xff > 0 {
-		return ErrInvalidAddress
+		return "", nil, &AddressError{Err: bech32.ErrInvalidLength}
 	}
 
-	return nil
-}
-
-func bech32Convert8to5(data []byte) []byte {
-	var acc, bits int
-
-	res := make([]byte, 0, 52)
-
-	for _, b := range data {
-		acc = (acc << 8) | int(b)
-		bits += 8
+	if pos := bech32InvalidPrefixChars(strings.ToLower(s[:sep])); pos != nil {
+		return "", nil, &AddressError{Err: ErrInvalidPrefix, Positions: pos}
+	}
 
-		for bits >= 5 {
-			bits -= 5
-			res = append(res, bech32Abc[acc>>bits&0x1f])
+	pfx, data, enc, err := bech32.Decode(s)
+	if err != nil {
+		var e *bech32.Error
+		if errors.As(err, &e) {
+			return "", nil, &AddressError{Err: e.Err, Positions: e.Positions}
 		}
-	}
 
-	if bits > 0 {
-		res = append(res, bech32Abc[acc<<(5-bits)&0x1f])
+		return "", nil, &AddressError{Err: err}
 	}
 
-	return res
-}
-
-func bech32CreateChecksum(prefix string, data []byte) []byte {
-	values := bech32PrefixExpand(prefix)
-
-	for _, v := range data {
-		values = append(values, strings.IndexByte(bech32Abc, v))
+	if enc != bech32.Bech32 {
+		return "", nil, &AddressError{Err: bech32.ErrInvalidEncoding}
 	}
 
-	polymod := bech32PolyMod(append(values, 0, 0, 0, 0, 0, 0)) ^ 1
-	checksum := make([]byte, 6)
-
-	for i := range checksum {
-		checksum[i] = bech32Abc[byte((polymod>>uint(5*(5-i)))&31)] //nolint:gomnd
+	pub, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", nil, &AddressError{Err: err, Positions: []int{len(s) - 7}}
 	}
 
-	return checksum
+	return pfx, pub, nil
 }
 
-func bech32PolyMod(values []int) int {
-	chk := 1
-
-	for _, v := range values {
-		b := chk >> 25               //nolint:gomnd
-		chk = (chk&0x1ffffff)<<5 ^ v //nolint:gomnd
-		chk = bech32PolyModGen(chk, b)
+func bech32InvalidPrefixChars(s string) (pos []int) {
+	if s == pfxGenesis {
+		return nil
 	}
 
-	return chk
-}
+	if len(s) != pfxLen {
+		pos = make([]int, len(s))
 
-func bech32PolyModGen(chk, b int) int {
-	for i, g := range [...]int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3} {
-		if (b>>i)&1 == 1 {
-			chk ^= g
+		for i := range pos {
+			pos[i] = i
 		}
-	}
-
-	return chk
-}
 
-func bech32PrefixExpand(p string) []int {
-	l := len(p)
-	r := make([]int, l*2+1)
+		return pos
+	}
 
-	for i, s := range p {
-		r[i] = int(s) >> 5     //nolint:gomnd
-		r[i+l+1] = int(s) & 31 //nolint:gomnd
+	for i := range s {
+		if strings.IndexByte(prefixAbc, s[i]) < 1 {
+			pos = append(pos, i)
+		}
 	}
 
-	return r
+	return pos
 }
 
-func bech32VerifyChecksum(prefix string, data []byte) bool {
-	b := bech32PrefixExpand(prefix)
-
-	for _, v := range data {
-		b = append(b, strings.IndexByte(bech32Abc, v))
-	}
-
-	return bech32PolyMod(b) == 1
+func bech32VerifyPrefix(s string) bool {
+	return bech32InvalidPrefixChars(s) == nil
 }
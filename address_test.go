@@ -21,10 +21,15 @@
 package libumi_test
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/umitop/libumi"
+	"github.com/umitop/libumi/bech32"
 )
 
 func TestBech32(t *testing.T) {
@@ -117,3 +122,105 @@ func TestVerifyAddressInvalidVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestAddress_Bech32InvalidVersion(t *testing.T) {
+	for _, v := range []uint16{1, 31, 0x7c00, 0x7fff} {
+		adr := libumi.NewAddress().SetVersion(v)
+
+		s := adr.Bech32()
+		if len(s) != 62 || !strings.HasPrefix(s, adr.Prefix()+"1") {
+			t.Fatalf("Expected: 62 characters, got: %q", s)
+		}
+
+		if _, err := libumi.NewAddressFromBech32(s); err == nil {
+			t.Fatalf("Expected: error, got: nil")
+		}
+	}
+}
+
+func TestAddress_Key(t *testing.T) {
+	exp, _ := libumi.NewAddressFromBech32("aaa1nfgzzgkr3nd69jes5kw87s2tuv46mhmrqpnw8ksffaujycenxx6sl48tkv")
+	act := exp.Key().Address()
+
+	if act.Bech32() != exp.Bech32() {
+		t.Fatalf("Expected: %s, got: %s", exp.Bech32(), act.Bech32())
+	}
+
+	m := map[libumi.AddressKey]int{exp.Key(): 1}
+	if m[libumi.NewAddress().SetPrefix("aaa").SetPublicKey(exp.PublicKey()).Key()] != 1 {
+		t.Fatalf("Expected: equal keys for equal addresses")
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	const valid = "aaa1nfgzzgkr3nd69jes5kw87s2tuv46mhmrqpnw8ksffaujycenxx6sl48tkv"
+
+	tests := []struct {
+		in  string
+		exp error
+		pos []int
+	}{
+		{valid[:20] + "q" + valid[21:], bech32.ErrInvalidChecksum, []int{20}},
+		{valid[:5] + "x" + valid[6:50] + "z" + valid[51:], bech32.ErrInvalidChecksum, []int{5, 50}},
+		{valid[:60] + "b" + valid[61:], bech32.ErrInvalidCharacter, []int{60}},
+		{valid[:3] + "l" + valid[4:], bech32.ErrInvalidSeparator, []int{3}},
+		{valid[:10] + "Q" + valid[11:], bech32.ErrMixedCase, []int{10}},
+		{valid[:30], bech32.ErrInvalidLength, nil},
+		{"a1a" + valid[3:], libumi.ErrInvalidPrefix, []int{1}},
+		{"aaaa" + valid[3:], libumi.ErrInvalidPrefix, []int{0, 1, 2, 3}},
+		{"umi1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqlfceute", bech32.ErrInvalidPadding, []int{55}},
+		{"umi1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqkgj5ys", bech32.ErrInvalidEncoding, nil},
+	}
+
+	for _, test := range tests {
+		_, err := libumi.ParseAddress(test.in)
+
+		var e *libumi.AddressError
+		if !errors.As(err, &e) {
+			t.Fatalf("%s Expected: *AddressError, got: %v", test.in, err)
+		}
+
+		if !errors.Is(err, test.exp) || !errors.Is(err, libumi.ErrInvalidAddress) {
+			t.Fatalf("%s Expected: %v, got: %v", test.in, test.exp, err)
+		}
+
+		if fmt.Sprint(e.Positions) != fmt.Sprint(test.pos) {
+			t.Fatalf("%s Expected: %v, got: %v", test.in, test.pos, e.Positions)
+		}
+	}
+
+	if _, err := libumi.ParseAddress(strings.ToUpper(valid)); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+}
+
+func TestNewAddressFromBech32Mode(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/address_vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests []struct {
+		Address string
+		Lenient bool
+		Strict  string
+	}
+
+	if err := json.Unmarshal(b, &tests); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		_, err := libumi.NewAddressFromBech32Mode(test.Address, libumi.Bech32Lenient)
+		if (err == nil) != test.Lenient {
+			t.Fatalf("%q Expected: %v, got: %v", test.Address, test.Lenient, err)
+		}
+
+		_, err = libumi.NewAddressFromBech32Mode(test.Address, libumi.Bech32Strict)
+
+		var e *libumi.AddressError
+		if test.Strict == "" && err != nil || test.Strict != "" && (!errors.As(err, &e) || e.Err.Error() != test.Strict) {
+			t.Fatalf("%q Expected: %v, got: %v", test.Address, test.Strict, err)
+		}
+	}
+}
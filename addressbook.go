@@ -0,0 +1,287 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxLabelLength    = 64
+	bech32ChecksumLen = 6
+)
+
+// Errors.
+var (
+	ErrInvalidLabel     = errors.New("invalid label")
+	ErrDuplicateAddress = errors.New("duplicate address")
+)
+
+// AddressBookEntry ...
+type AddressBookEntry struct {
+	Label   string
+	Address Address
+}
+
+type addressBookJSON struct {
+	Label   string `json:"label"`
+	Address string `json:"address"`
+}
+
+// AddressBook is a list of labeled addresses in insertion order. The zero
+// value is an empty book ready to use.
+type AddressBook struct {
+	entries []AddressBookEntry
+	index   map[AddressKey]int
+}
+
+// NewAddressBook ...
+func NewAddressBook() *AddressBook {
+	return &AddressBook{index: make(map[AddressKey]int)}
+}
+
+// Add validates and stores the entry. It returns the existing entries whose
+// bech32 form, checksum excluded, differs from the new address by a single
+// character, which usually means one of them was mistyped or belongs to
+// a neighbouring structure.
+func (b *AddressBook) Add(label string, adr Address) (similar []AddressBookEntry, err error) {
+	if err := VerifyAddress(adr); err != nil {
+		return nil, err
+	}
+
+	if !labelIsValid(label) {
+		return nil, ErrInvalidLabel
+	}
+
+	if _, ok := b.index[adr.Key()]; ok {
+		return nil, ErrDuplicateAddress
+	}
+
+	if b.index == nil {
+		b.index = make(map[AddressKey]int)
+	}
+
+	s := bech32Body(adr)
+
+	for _, e := range b.entries {
+		if hammingDistance(s, bech32Body(e.Address)) == 1 {
+			similar = append(similar, e)
+		}
+	}
+
+	b.index[adr.Key()] = len(b.entries)
+	b.entries = append(b.entries, AddressBookEntry{Label: label, Address: append(Address{}, adr...)})
+
+	return similar, nil
+}
+
+// Remove ...
+func (b *AddressBook) Remove(adr Address) bool {
+	i, ok := b.index[adr.Key()]
+	if !ok {
+		return false
+	}
+
+	b.entries = append(b.entries[:i], b.entries[i+1:]...)
+	delete(b.index, adr.Key())
+
+	for j := i; j < len(b.entries); j++ {
+		b.index[b.entries[j].Address.Key()] = j
+	}
+
+	return true
+}
+
+// Lookup ...
+func (b *AddressBook) Lookup(adr Address) (AddressBookEntry, bool) {
+	if i, ok := b.index[adr.Key()]; ok {
+		return b.entries[i], true
+	}
+
+	return AddressBookEntry{}, false
+}
+
+// Len ...
+func (b *AddressBook) Len() int {
+	return len(b.entries)
+}
+
+// Entries ...
+func (b *AddressBook) Entries() []AddressBookEntry {
+	return append([]AddressBookEntry{}, b.entries...)
+}
+
+// Prefixes returns the sorted structure prefixes present in the book.
+func (b *AddressBook) Prefixes() []string {
+	seen := make(map[string]struct{})
+	p := make([]string, 0)
+
+	for _, e := range b.entries {
+		if _, ok := seen[e.Address.Prefix()]; !ok {
+			seen[e.Address.Prefix()] = struct{}{}
+			p = append(p, e.Address.Prefix())
+		}
+	}
+
+	sort.Strings(p)
+
+	return p
+}
+
+// Group returns the entries of one structure in insertion order.
+func (b *AddressBook) Group(prefix string) []AddressBookEntry {
+	var g []AddressBookEntry
+
+	for _, e := range b.entries {
+		if e.Address.Prefix() == prefix {
+			g = append(g, e)
+		}
+	}
+
+	return g
+}
+
+// MarshalJSON ...
+func (b *AddressBook) MarshalJSON() ([]byte, error) {
+	v := make([]addressBookJSON, len(b.entries))
+
+	for i, e := range b.entries {
+		v[i] = addressBookJSON{Label: e.Label, Address: e.Address.Bech32()}
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON replaces the book with the decoded entries. Invalid entries are
+// reported as a *RowError with the 1-based position in the list.
+func (b *AddressBook) UnmarshalJSON(data []byte) error {
+	var v []addressBookJSON
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	nb := NewAddressBook()
+
+	for i, e := range v {
+		if err := nb.addBech32(e.Label, e.Address); err != nil {
+			return &RowError{Row: i + 1, Err: err}
+		}
+	}
+
+	*b = *nb
+
+	return nil
+}
+
+// WriteCSV writes "label,address" rows with a header.
+func (b *AddressBook) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	_ = cw.Write([]string{"label", "address"})
+
+	for _, e := range b.entries {
+		_ = cw.Write([]string{e.Label, e.Address.Bech32()})
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// ReadAddressBookCSV reads rows written by WriteCSV. The header is optional.
+func ReadAddressBookCSV(r io.Reader) (*AddressBook, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewAddressBook()
+
+	for i, rec := range records {
+		if i == 0 && len(rec) == 2 && rec[0] == "label" && rec[1] == "address" {
+			continue
+		}
+
+		if len(rec) != 2 {
+			return nil, &RowError{Row: i + 1, Err: ErrInvalidRow}
+		}
+
+		if err := b.addBech32(rec[0], strings.TrimSpace(rec[1])); err != nil {
+			return nil, &RowError{Row: i + 1, Err: err}
+		}
+	}
+
+	return b, nil
+}
+
+func (b *AddressBook) addBech32(label, s string) error {
+	adr, err := NewAddressFromBech32(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.Add(label, adr)
+
+	return err
+}
+
+func labelIsValid(s string) bool {
+	if len(s) == 0 || len(s) > maxLabelLength || !utf8.ValidString(s) || strings.TrimSpace(s) != s {
+		return false
+	}
+
+	return strings.IndexFunc(s, func(r rune) bool { return !unicode.IsPrint(r) }) == -1
+}
+
+// bech32Body strips the checksum, two valid checksums never differ
+// in a single character.
+func bech32Body(adr Address) string {
+	s := adr.Bech32()
+
+	return s[:len(s)-bech32ChecksumLen]
+}
+
+func hammingDistance(a, b string) int {
+	if len(a) != len(b) {
+		return -1
+	}
+
+	d := 0
+
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			d++
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func TestAddressBook(t *testing.T) {
+	pub, _, aaa, _ := libumi.GenerateKey(rand.Reader, "aaa")
+	aab, _ := libumi.AddressFromPublicKey(pub, "aab")
+	_, _, umi, _ := libumi.GenerateKey(rand.Reader, "umi")
+
+	b := libumi.NewAddressBook()
+
+	if similar, err := b.Add("alice", aaa); err != nil || len(similar) != 0 {
+		t.Fatalf("Expected: nil 0, got: %v %d", err, len(similar))
+	}
+
+	if _, err := b.Add("bob", umi); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	similar, err := b.Add("alice 2", aab)
+	if err != nil || len(similar) != 1 || similar[0].Label != "alice" {
+		t.Fatalf("Expected: nil [alice], got: %v %v", err, similar)
+	}
+
+	if _, err := b.Add("again", aaa); !errors.Is(err, libumi.ErrDuplicateAddress) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrDuplicateAddress, err)
+	}
+
+	if p := strings.Join(b.Prefixes(), ","); p != "aaa,aab,umi" {
+		t.Fatalf("Expected: aaa,aab,umi, got: %s", p)
+	}
+
+	if g := b.Group("umi"); len(g) != 1 || g[0].Label != "bob" {
+		t.Fatalf("Expected: [bob], got: %v", g)
+	}
+
+	if e, ok := b.Lookup(aab); !ok || e.Label != "alice 2" {
+		t.Fatalf("Expected: alice 2, got: %v %v", e.Label, ok)
+	}
+
+	if !b.Remove(aaa) || b.Remove(aaa) || b.Len() != 2 {
+		t.Fatalf("Expected: 2 entries after remove, got: %d", b.Len())
+	}
+
+	if e, ok := b.Lookup(aab); !ok || e.Label != "alice 2" {
+		t.Fatalf("Expected: alice 2, got: %v %v", e.Label, ok)
+	}
+}
+
+func TestAddressBookZero(t *testing.T) {
+	_, _, adr, _ := libumi.GenerateKey(rand.Reader, "umi")
+
+	var b libumi.AddressBook
+
+	if _, ok := b.Lookup(adr); ok || b.Remove(adr) {
+		t.Fatalf("Expected: empty book")
+	}
+
+	if _, err := b.Add("bob", adr); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if e, ok := b.Lookup(adr); !ok || e.Label != "bob" {
+		t.Fatalf("Expected: bob, got: %v %v", e.Label, ok)
+	}
+
+	var nb libumi.AddressBook
+
+	data, _ := json.Marshal(&b)
+	if err := json.Unmarshal(data, &nb); err != nil || nb.Len() != 1 {
+		t.Fatalf("Expected: nil 1, got: %v %d", err, nb.Len())
+	}
+}
+
+func TestAddressBookInvalid(t *testing.T) {
+	_, _, adr, _ := libumi.GenerateKey(rand.Reader, "umi")
+	b := libumi.NewAddressBook()
+
+	for _, label := range []string{"", " bob", "bob\n", "a\x00b", "\xff", strings.Repeat("a", 65)} {
+		if _, err := b.Add(label, adr); !errors.Is(err, libumi.ErrInvalidLabel) {
+			t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidLabel, err)
+		}
+	}
+
+	if _, err := b.Add("bob", adr[:10]); err == nil {
+		t.Fatalf("Expected: error, got: nil")
+	}
+
+	if _, err := b.Add("bob", libumi.Address(make([]byte, libumi.AddressLength)).SetVersion(1)); err == nil {
+		t.Fatalf("Expected: error, got: nil")
+	}
+
+	if b.Len() != 0 {
+		t.Fatalf("Expected: 0, got: %d", b.Len())
+	}
+}
+
+func TestAddressBookJSON(t *testing.T) {
+	_, _, a, _ := libumi.GenerateKey(rand.Reader, "aaa")
+	_, _, c, _ := libumi.GenerateKey(rand.Reader, "umi")
+
+	b := libumi.NewAddressBook()
+	_, _ = b.Add("Алиса", a)
+	_, _ = b.Add("carol, \"c\"", c)
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	nb := libumi.NewAddressBook()
+	if err := json.Unmarshal(data, nb); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if e := nb.Entries(); len(e) != 2 || e[0].Label != "Алиса" || e[1].Address.Bech32() != c.Bech32() {
+		t.Fatalf("Expected: 2 entries, got: %v", e)
+	}
+
+	bad := `[{"label":"a","address":"` + a.Bech32() + `"},{"label":"b","address":"` + a.Bech32() + `"}]`
+
+	var re *libumi.RowError
+	if err := json.Unmarshal([]byte(bad), nb); !errors.As(err, &re) || re.Row != 2 ||
+		!errors.Is(err, libumi.ErrDuplicateAddress) {
+		t.Fatalf("Expected: row 2 %v, got: %v", libumi.ErrDuplicateAddress, err)
+	}
+
+	if nb.Len() != 2 {
+		t.Fatalf("Expected: 2, got: %d", nb.Len())
+	}
+}
+
+func TestAddressBookCSV(t *testing.T) {
+	_, _, a, _ := libumi.GenerateKey(rand.Reader, "aaa")
+	_, _, c, _ := libumi.GenerateKey(rand.Reader, "umi")
+
+	b := libumi.NewAddressBook()
+	_, _ = b.Add("alice", a)
+	_, _ = b.Add("carol, \"c\"", c)
+
+	var buf bytes.Buffer
+	if err := b.WriteCSV(&buf); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "label,address\n") {
+		t.Fatalf("Expected: header, got: %q", buf.String())
+	}
+
+	nb, err := libumi.ReadAddressBookCSV(&buf)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if e := nb.Entries(); len(e) != 2 || e[1].Label != "carol, \"c\"" || e[0].Address.Bech32() != a.Bech32() {
+		t.Fatalf("Expected: 2 entries, got: %v", e)
+	}
+
+	tests := []struct {
+		csv string
+		row int
+	}{
+		{"alice," + a.Bech32() + "\nbob\n", 2},
+		{"label,address\nalice," + a.Bech32()[:20] + "\n", 2},
+		{"alice," + a.Bech32() + "\n," + c.Bech32() + "\n", 2},
+	}
+
+	for _, test := range tests {
+		var re *libumi.RowError
+		if _, err := libumi.ReadAddressBookCSV(strings.NewReader(test.csv)); !errors.As(err, &re) || re.Row != test.row {
+			t.Fatalf("Expected: row %d, got: %v", test.row, err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"errors"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// AmountDecimals ...
+const AmountDecimals = 2
+
+const amountScale = 100
+
+// Errors.
+var (
+	ErrInvalidAmount  = errors.New("invalid amount")
+	ErrAmountOverflow = errors.New("amount overflow")
+)
+
+// Amount is a value in the smallest indivisible units, 1.00 UMI = 100 units.
+type Amount uint64
+
+// ParseAmount ...
+func ParseAmount(s string) (Amount, error) {
+	whole, frac := s, ""
+
+	if i := strings.IndexByte(s, '.'); i != -1 {
+		whole, frac = s[:i], s[i+1:]
+
+		if len(frac) == 0 || len(frac) > AmountDecimals {
+			return 0, ErrInvalidAmount
+		}
+	}
+
+	if !isDigits(whole) || !isDigits(frac+"0") {
+		return 0, ErrInvalidAmount
+	}
+
+	w, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		return 0, ErrAmountOverflow
+	}
+
+	f, _ := strconv.ParseUint((frac + "00")[:AmountDecimals], 10, 64)
+
+	a, err := Amount(w).Mul(amountScale)
+	if err != nil {
+		return 0, err
+	}
+
+	return a.Add(Amount(f))
+}
+
+// String ...
+func (a Amount) String() string {
+	f := strconv.FormatUint(uint64(a%amountScale)+amountScale, 10)
+
+	return strconv.FormatUint(uint64(a/amountScale), 10) + "." + f[1:]
+}
+
+// Add ...
+func (a Amount) Add(b Amount) (Amount, error) {
+	sum, carry := bits.Add64(uint64(a), uint64(b), 0)
+	if carry != 0 {
+		return 0, ErrAmountOverflow
+	}
+
+	return Amount(sum), nil
+}
+
+// Sub ...
+func (a Amount) Sub(b Amount) (Amount, error) {
+	diff, borrow := bits.Sub64(uint64(a), uint64(b), 0)
+	if borrow != 0 {
+		return 0, ErrAmountOverflow
+	}
+
+	return Amount(diff), nil
+}
+
+// Mul ...
+func (a Amount) Mul(n uint64) (Amount, error) {
+	hi, lo := bits.Mul64(uint64(a), n)
+	if hi != 0 {
+		return 0, ErrAmountOverflow
+	}
+
+	return Amount(lo), nil
+}
+
+// MarshalText ...
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText ...
+func (a *Amount) UnmarshalText(b []byte) error {
+	v, err := ParseAmount(string(b))
+	if err != nil {
+		return err
+	}
+
+	*a = v
+
+	return nil
+}
+
+// MarshalJSON ...
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON accepts both a quoted decimal string and a bare JSON number,
+// null leaves the amount unchanged.
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	s := string(b)
+
+	if s == "null" {
+		return nil
+	}
+
+	if len(s) > 1 && s[0] == '"' {
+		u, err := strconv.Unquote(s)
+		if err != nil {
+			return ErrInvalidAmount
+		}
+
+		s = u
+	}
+
+	return a.UnmarshalText([]byte(s))
+}
+
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for i := range s {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp libumi.Amount
+		str string
+	}{
+		{"0", 0, "0.00"},
+		{"0.01", 1, "0.01"},
+		{"1", 100, "1.00"},
+		{"1.5", 150, "1.50"},
+		{"001.05", 105, "1.05"},
+		{"184467440737095516.15", math.MaxUint64, "184467440737095516.15"},
+	}
+
+	for _, test := range tests {
+		act, err := libumi.ParseAmount(test.in)
+		if err != nil {
+			t.Fatalf("%s Expected: nil, got: %v", test.in, err)
+		}
+
+		if act != test.exp {
+			t.Fatalf("Expected: %d, got: %d", test.exp, act)
+		}
+
+		if act.String() != test.str {
+			t.Fatalf("Expected: %s, got: %s", test.str, act.String())
+		}
+	}
+}
+
+func TestParseAmountError(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp error
+	}{
+		{"", libumi.ErrInvalidAmount},
+		{".5", libumi.ErrInvalidAmount},
+		{"1.", libumi.ErrInvalidAmount},
+		{"1.234", libumi.ErrInvalidAmount},
+		{"-1", libumi.ErrInvalidAmount},
+		{"+1", libumi.ErrInvalidAmount},
+		{"1,5", libumi.ErrInvalidAmount},
+		{" 1", libumi.ErrInvalidAmount},
+		{"184467440737095516.16", libumi.ErrAmountOverflow},
+		{"184467440737095517", libumi.ErrAmountOverflow},
+		{"99999999999999999999", libumi.ErrAmountOverflow},
+	}
+
+	for _, test := range tests {
+		_, err := libumi.ParseAmount(test.in)
+		if !errors.Is(err, test.exp) {
+			t.Fatalf("%q Expected: %v, got: %v", test.in, test.exp, err)
+		}
+	}
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	top := libumi.Amount(math.MaxUint64)
+
+	if _, err := top.Add(1); !errors.Is(err, libumi.ErrAmountOverflow) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrAmountOverflow, err)
+	}
+
+	if _, err := libumi.Amount(1).Sub(2); !errors.Is(err, libumi.ErrAmountOverflow) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrAmountOverflow, err)
+	}
+
+	if _, err := top.Mul(2); !errors.Is(err, libumi.ErrAmountOverflow) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrAmountOverflow, err)
+	}
+
+	act, _ := libumi.Amount(150).Mul(3)
+	act, _ = act.Sub(50)
+	act, _ = act.Add(1)
+
+	if act != 401 {
+		t.Fatalf("Expected: %d, got: %d", 401, act)
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	b, _ := json.Marshal(struct{ V libumi.Amount }{12345})
+	if string(b) != `{"V":"123.45"}` {
+		t.Fatalf("Expected: %s, got: %s", `{"V":"123.45"}`, b)
+	}
+
+	var v struct{ A, B libumi.Amount }
+	if err := json.Unmarshal([]byte(`{"A":"1.5","B":2.25}`), &v); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if v.A != 150 || v.B != 225 {
+		t.Fatalf("Expected: 150 225, got: %d %d", v.A, v.B)
+	}
+
+	if err := json.Unmarshal([]byte(`{"A":null,"B":null}`), &v); err != nil || v.A != 150 || v.B != 225 {
+		t.Fatalf("Expected: nil 150 225, got: %v %d %d", err, v.A, v.B)
+	}
+
+	if err := json.Unmarshal([]byte(`{"A":"1.555"}`), &v); !errors.Is(err, libumi.ErrInvalidAmount) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidAmount, err)
+	}
+}
+
+func TestTransaction_Amount(t *testing.T) {
+	tx := libumi.NewTransaction().SetAmount(42)
+
+	if tx.Value() != 42 || tx.Amount() != 42 {
+		t.Fatalf("Expected: %d, got: %d", 42, tx.Amount())
+	}
+}
@@ -0,0 +1,446 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package bech32 implements the bech32 (BIP-173) and bech32m (BIP-350) encodings
+// with an arbitrary human-readable part and data length.
+package bech32
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Charset ...
+const Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	checksumLen  = 6
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// Encoding ...
+type Encoding uint8
+
+// Encodings.
+const (
+	Bech32 Encoding = iota + 1
+	Bech32m
+)
+
+// Errors.
+var (
+	ErrInvalidHRP       = errors.New("invalid human-readable part")
+	ErrInvalidLength    = errors.New("invalid length")
+	ErrInvalidSeparator = errors.New("invalid separator")
+	ErrInvalidCharacter = errors.New("invalid character")
+	ErrMixedCase        = errors.New("mixed case")
+	ErrInvalidChecksum  = errors.New("invalid checksum")
+	ErrInvalidPadding   = errors.New("invalid padding")
+	ErrInvalidEncoding  = errors.New("invalid encoding")
+)
+
+// Error reports a decoding failure and, when known, the positions of the
+// offending characters in the input string.
+type Error struct {
+	Err       error
+	Positions []int
+}
+
+// Error ...
+func (e *Error) Error() string {
+	var s strings.Builder
+
+	s.WriteString(e.Err.Error())
+
+	for i, p := range e.Positions {
+		switch {
+		case i > 0:
+			s.WriteString(", ")
+		case len(e.Positions) == 1:
+			s.WriteString(" at position ")
+		default:
+			s.WriteString(" at positions ")
+		}
+
+		s.WriteString(strconv.Itoa(p))
+	}
+
+	return s.String()
+}
+
+// Unwrap ...
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Encode encodes 5-bit values.
+func Encode(hrp string, data []byte, enc Encoding) (string, error) {
+	if err := verifyHRP(hrp); err != nil {
+		return "", err
+	}
+
+	return encode(hrp, data, enc)
+}
+
+// EncodeBytes encodes 8-bit data.
+func EncodeBytes(hrp string, data []byte, enc Encoding) (string, error) {
+	b, err := ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(hrp, b, enc)
+}
+
+// EncodeBytesUnchecked encodes 8-bit data like EncodeBytes but accepts any
+// human-readable part, the result does not decode if the part is invalid.
+func EncodeBytesUnchecked(hrp string, data []byte, enc Encoding) (string, error) {
+	b, err := ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	return encode(hrp, b, enc)
+}
+
+func encode(hrp string, data []byte, enc Encoding) (string, error) {
+	chk, err := createChecksum(hrp, data, enc)
+	if err != nil {
+		return "", err
+	}
+
+	var s strings.Builder
+
+	s.Grow(len(hrp) + 1 + len(data) + checksumLen)
+	s.WriteString(hrp)
+	s.WriteByte('1')
+
+	for _, v := range data {
+		if v > 31 {
+			return "", ErrInvalidCharacter
+		}
+
+		s.WriteByte(Charset[v])
+	}
+
+	for _, v := range chk {
+		s.WriteByte(Charset[v])
+	}
+
+	return s.String(), nil
+}
+
+// Decode returns the human-readable part in lower case and 5-bit values.
+// Errors are of type *Error, for an invalid checksum it holds the likely
+// positions of up to two substituted characters if they can be located.
+func Decode(s string) (hrp string, data []byte, enc Encoding, err error) {
+	if err = verifyCase(s); err != nil {
+		return "", nil, 0, err
+	}
+
+	s = strings.ToLower(s)
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep == -1 {
+		return "", nil, 0, &Error{Err: ErrInvalidSeparator}
+	}
+
+	hrp = s[:sep]
+	if err = verifyHRP(hrp); err != nil {
+		return "", nil, 0, err
+	}
+
+	if len(s)-sep-1 < checksumLen {
+		return "", nil, 0, &Error{Err: ErrInvalidLength}
+	}
+
+	data = make([]byte, 0, len(s)-sep-1)
+
+	for i := sep + 1; i < len(s); i++ {
+		v := strings.IndexByte(Charset, s[i])
+		if v == -1 {
+			return "", nil, 0, &Error{Err: ErrInvalidCharacter, Positions: []int{i}}
+		}
+
+		data = append(data, byte(v))
+	}
+
+	if enc = verifyChecksum(hrp, data); enc == 0 {
+		return "", nil, 0, &Error{Err: ErrInvalidChecksum, Positions: locate(hrp, data, sep+1)}
+	}
+
+	return hrp, data[:len(data)-checksumLen], enc, nil
+}
+
+// LocateErrors returns the positions of up to two substituted characters in
+// the data part of a string with an invalid checksum of the given encoding.
+// It returns nil if the checksum is valid or the errors cannot be located.
+func LocateErrors(s string, enc Encoding) []int {
+	c, err := encodingConst(enc)
+	if err != nil {
+		return nil
+	}
+
+	s = strings.ToLower(s)
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep == -1 {
+		return nil
+	}
+
+	data := make([]byte, 0, len(s)-sep-1)
+
+	for i := sep + 1; i < len(s); i++ {
+		v := strings.IndexByte(Charset, s[i])
+		if v == -1 {
+			return nil
+		}
+
+		data = append(data, byte(v))
+	}
+
+	return locateErrors(PolyMod(append(hrpExpand(s[:sep]), data...))^c, len(data), sep+1)
+}
+
+// DecodeBytes returns the human-readable part in lower case and 8-bit data.
+func DecodeBytes(s string) (hrp string, data []byte, enc Encoding, err error) {
+	if hrp, data, enc, err = Decode(s); err != nil {
+		return "", nil, 0, err
+	}
+
+	if data, err = ConvertBits(data, 5, 8, false); err != nil {
+		return "", nil, 0, err
+	}
+
+	return hrp, data, enc, nil
+}
+
+// ConvertBits regroups data from frombits-bit to tobits-bit values.
+func ConvertBits(data []byte, frombits, tobits uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+
+	maxv := uint(1)<<tobits - 1
+	out := make([]byte, 0, len(data)*int(frombits)/int(tobits)+1)
+
+	for _, b := range data {
+		if uint(b)>>frombits != 0 {
+			return nil, ErrInvalidCharacter
+		}
+
+		acc = acc<<frombits | uint(b)
+		bits += frombits
+
+		for bits >= tobits {
+			bits -= tobits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(tobits-bits)&maxv))
+		}
+	} else if bits >= frombits || acc<<(tobits-bits)&maxv != 0 {
+		return nil, ErrInvalidPadding
+	}
+
+	return out, nil
+}
+
+// PolyMod ...
+func PolyMod(values []byte) uint32 {
+	chk := uint32(1)
+
+	for _, v := range values {
+		chk = polyModStep(chk, v)
+	}
+
+	return chk
+}
+
+func polyModStep(chk uint32, v byte) uint32 {
+	b := chk >> 25
+	chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+
+	for i, g := range [...]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3} {
+		if (b>>uint(i))&1 == 1 {
+			chk ^= g
+		}
+	}
+
+	return chk
+}
+
+func locate(hrp string, data []byte, offset int) []int {
+	residue := PolyMod(append(hrpExpand(hrp), data...))
+
+	a := locateErrors(residue^bech32Const, len(data), offset)
+	b := locateErrors(residue^bech32mConst, len(data), offset)
+
+	if a == nil || (b != nil && len(b) < len(a)) {
+		return b
+	}
+
+	return a
+}
+
+// locateErrors finds an error vector e of weight one or two such that
+// PolyMod(e) equals the syndrome. The checksum is a BCH code with distance
+// enough to make such a vector unique for strings of reasonable length.
+// PolyMod is linear over GF(2) when started from zero, so syndromes of single
+// substitutions are tabulated and pairs are found by a lookup.
+func locateErrors(syndrome uint32, n int, offset int) []int {
+	if syndrome == 0 {
+		return nil
+	}
+
+	type substitution struct{ pos, val int }
+
+	table := make(map[uint32]substitution, n*31)
+	syn := make([][32]uint32, n)
+
+	for v := 1; v < 32; v++ {
+		chk := uint32(v)
+
+		for p := n - 1; p >= 0; p-- {
+			syn[p][v] = chk
+			table[chk] = substitution{p, v}
+			chk = polyModStep(chk, 0)
+		}
+	}
+
+	if e, ok := table[syndrome]; ok {
+		return []int{offset + e.pos}
+	}
+
+	for p := 0; p < n; p++ {
+		for v := 1; v < 32; v++ {
+			if e, ok := table[syndrome^syn[p][v]]; ok && e.pos > p {
+				return []int{offset + p, offset + e.pos}
+			}
+		}
+	}
+
+	return nil
+}
+
+func hrpExpand(hrp string) []byte {
+	l := len(hrp)
+	r := make([]byte, l*2+1)
+
+	for i := 0; i < l; i++ {
+		r[i] = hrp[i] >> 5
+		r[i+l+1] = hrp[i] & 31
+	}
+
+	return r
+}
+
+func encodingConst(enc Encoding) (uint32, error) {
+	switch enc {
+	case Bech32:
+		return bech32Const, nil
+	case Bech32m:
+		return bech32mConst, nil
+	default:
+		return 0, ErrInvalidEncoding
+	}
+}
+
+func createChecksum(hrp string, data []byte, enc Encoding) ([]byte, error) {
+	c, err := encodingConst(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	values := append(hrpExpand(hrp), data...)
+	polymod := PolyMod(append(values, 0, 0, 0, 0, 0, 0)) ^ c
+	checksum := make([]byte, checksumLen)
+
+	for i := range checksum {
+		checksum[i] = byte(polymod>>uint(5*(5-i))) & 31
+	}
+
+	return checksum, nil
+}
+
+func verifyChecksum(hrp string, data []byte) Encoding {
+	switch PolyMod(append(hrpExpand(hrp), data...)) {
+	case bech32Const:
+		return Bech32
+	case bech32mConst:
+		return Bech32m
+	default:
+		return 0
+	}
+}
+
+func verifyHRP(hrp string) error {
+	if len(hrp) == 0 {
+		return &Error{Err: ErrInvalidHRP}
+	}
+
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 || (hrp[i] >= 'A' && hrp[i] <= 'Z') {
+			return &Error{Err: ErrInvalidHRP, Positions: []int{i}}
+		}
+	}
+
+	return nil
+}
+
+// verifyCase reports the first character of the minority case on mixed case.
+func verifyCase(s string) error {
+	var first, count [2]int
+
+	for i := 0; i < len(s); i++ {
+		c, k := s[i], -1
+
+		switch {
+		case c < 33 || c > 126:
+			return &Error{Err: ErrInvalidCharacter, Positions: []int{i}}
+		case c >= 'a' && c <= 'z':
+			k = 0
+		case c >= 'A' && c <= 'Z':
+			k = 1
+		}
+
+		if k != -1 {
+			if count[k] == 0 {
+				first[k] = i
+			}
+
+			count[k]++
+		}
+	}
+
+	if count[0] == 0 || count[1] == 0 {
+		return nil
+	}
+
+	k := 1
+	if count[0] < count[1] {
+		k = 0
+	}
+
+	return &Error{Err: ErrMixedCase, Positions: []int{first[k]}}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package bech32_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi/bech32"
+)
+
+func TestDecodeValid(t *testing.T) {
+	tests := []struct {
+		s   string
+		enc bech32.Encoding
+	}{
+		{"A12UEL5L", bech32.Bech32},
+		{"a12uel5l", bech32.Bech32},
+		{"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs", bech32.Bech32},
+		{"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", bech32.Bech32},
+		{"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j", bech32.Bech32},
+		{"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", bech32.Bech32},
+		{"?1ezyfcl", bech32.Bech32},
+		{"A1LQFN3A", bech32.Bech32m},
+		{"a1lqfn3a", bech32.Bech32m},
+		{"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6", bech32.Bech32m},
+		{"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", bech32.Bech32m},
+		{"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8", bech32.Bech32m},
+		{"split1checkupstagehandshakeupstreamerranterredcaperredlc445v", bech32.Bech32m},
+		{"?1v759aa", bech32.Bech32m},
+	}
+
+	for _, test := range tests {
+		hrp, data, enc, err := bech32.Decode(test.s)
+		if err != nil {
+			t.Fatalf("%s Expected: nil, got: %v", test.s, err)
+		}
+
+		if enc != test.enc {
+			t.Fatalf("%s Expected: %v, got: %v", test.s, test.enc, enc)
+		}
+
+		act, _ := bech32.Encode(hrp, data, enc)
+		if act != strings.ToLower(test.s) {
+			t.Fatalf("Expected: %s, got: %s", strings.ToLower(test.s), act)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp error
+	}{
+		{"\x201nwldj5", bech32.ErrInvalidCharacter},
+		{"\x7f1axkwrx", bech32.ErrInvalidCharacter},
+		{"\x801eym55h", bech32.ErrInvalidCharacter},
+		{"pzry9x0s0muk", bech32.ErrInvalidSeparator},
+		{"1pzry9x0s0muk", bech32.ErrInvalidHRP},
+		{"x1b4n0q5v", bech32.ErrInvalidCharacter},
+		{"li1dgmt3", bech32.ErrInvalidLength},
+		{"de1lg7wt\xff", bech32.ErrInvalidCharacter},
+		{"A1G7SGD8", bech32.ErrInvalidChecksum},
+		{"10a06t8", bech32.ErrInvalidHRP},
+		{"1qzzfhee", bech32.ErrInvalidHRP},
+		{"a12UEL5L", bech32.ErrMixedCase},
+		{"a12uel5m", bech32.ErrInvalidChecksum},
+	}
+
+	for _, test := range tests {
+		_, _, _, err := bech32.Decode(test.s)
+		if !errors.Is(err, test.exp) {
+			t.Fatalf("%q Expected: %v, got: %v", test.s, test.exp, err)
+		}
+	}
+}
+
+func TestEncodeBytes(t *testing.T) {
+	for _, enc := range []bech32.Encoding{bech32.Bech32, bech32.Bech32m} {
+		for l := 0; l < 80; l++ {
+			exp := bytes.Repeat([]byte{byte(l)}, l)
+
+			s, err := bech32.EncodeBytes("tx", exp, enc)
+			if err != nil {
+				t.Fatalf("Expected: nil, got: %v", err)
+			}
+
+			hrp, act, dec, err := bech32.DecodeBytes(s)
+			if err != nil {
+				t.Fatalf("%s Expected: nil, got: %v", s, err)
+			}
+
+			if hrp != "tx" || dec != enc || !bytes.Equal(exp, act) {
+				t.Fatalf("Expected: %x, got: %x", exp, act)
+			}
+		}
+	}
+}
+
+func TestEncodeError(t *testing.T) {
+	if _, err := bech32.Encode("", nil, bech32.Bech32); !errors.Is(err, bech32.ErrInvalidHRP) {
+		t.Fatalf("Expected: %v, got: %v", bech32.ErrInvalidHRP, err)
+	}
+
+	if _, err := bech32.Encode("Umi", nil, bech32.Bech32); !errors.Is(err, bech32.ErrInvalidHRP) {
+		t.Fatalf("Expected: %v, got: %v", bech32.ErrInvalidHRP, err)
+	}
+
+	if _, err := bech32.Encode("umi", []byte{32}, bech32.Bech32); !errors.Is(err, bech32.ErrInvalidCharacter) {
+		t.Fatalf("Expected: %v, got: %v", bech32.ErrInvalidCharacter, err)
+	}
+
+	if _, err := bech32.Encode("umi", nil, 0); !errors.Is(err, bech32.ErrInvalidEncoding) {
+		t.Fatalf("Expected: %v, got: %v", bech32.ErrInvalidEncoding, err)
+	}
+}
+
+func TestEncodeBytesUnchecked(t *testing.T) {
+	exp, _ := bech32.EncodeBytes("umi", []byte{1, 2, 3}, bech32.Bech32)
+
+	if act, err := bech32.EncodeBytesUnchecked("umi", []byte{1, 2, 3}, bech32.Bech32); err != nil || act != exp {
+		t.Fatalf("Expected: %s, got: %s %v", exp, act, err)
+	}
+
+	s, err := bech32.EncodeBytesUnchecked("um\x7f", []byte{1, 2, 3}, bech32.Bech32)
+	if err != nil || !strings.HasPrefix(s, "um\x7f1") {
+		t.Fatalf("Expected: um\\x7f1..., got: %q %v", s, err)
+	}
+
+	if _, _, _, err := bech32.Decode(s); err == nil {
+		t.Fatalf("Expected: error, got: nil")
+	}
+
+	if _, err := bech32.EncodeBytesUnchecked("umi", nil, 0); !errors.Is(err, bech32.ErrInvalidEncoding) {
+		t.Fatalf("Expected: %v, got: %v", bech32.ErrInvalidEncoding, err)
+	}
+}
+
+func TestDecodeBytesPadding(t *testing.T) {
+	s, _ := bech32.Encode("umi", []byte{31}, bech32.Bech32)
+
+	if _, _, _, err := bech32.DecodeBytes(s); !errors.Is(err, bech32.ErrInvalidPadding) {
+		t.Fatalf("Expected: %v, got: %v", bech32.ErrInvalidPadding, err)
+	}
+}
+
+func TestDecodeErrorPositions(t *testing.T) {
+	valid, _ := bech32.EncodeBytes("umi", bytes.Repeat([]byte{0xa5}, 32), bech32.Bech32m)
+	flip := func(s string, i int) string {
+		c := bech32.Charset[(strings.IndexByte(bech32.Charset, s[i])+7)%32]
+
+		return s[:i] + string(c) + s[i+1:]
+	}
+
+	tests := []struct {
+		in  string
+		exp error
+		pos []int
+	}{
+		{flip(valid, 4), bech32.ErrInvalidChecksum, []int{4}},
+		{flip(valid, len(valid)-1), bech32.ErrInvalidChecksum, []int{len(valid) - 1}},
+		{flip(flip(valid, 10), 40), bech32.ErrInvalidChecksum, []int{10, 40}},
+		{valid[:7] + "o" + valid[8:], bech32.ErrInvalidCharacter, []int{7}},
+		{"Umi" + valid[3:], bech32.ErrMixedCase, []int{0}},
+		{"u\x00i" + valid[3:], bech32.ErrInvalidCharacter, []int{1}},
+	}
+
+	for _, test := range tests {
+		_, _, _, err := bech32.Decode(test.in)
+
+		var e *bech32.Error
+		if !errors.As(err, &e) || !errors.Is(err, test.exp) {
+			t.Fatalf("%q Expected: %v, got: %v", test.in, test.exp, err)
+		}
+
+		if fmt.Sprint(e.Positions) != fmt.Sprint(test.pos) {
+			t.Fatalf("%q Expected: %v, got: %v", test.in, test.pos, e.Positions)
+		}
+	}
+
+	if act := bech32.LocateErrors(flip(valid, 20), bech32.Bech32m); fmt.Sprint(act) != "[20]" {
+		t.Fatalf("Expected: [20], got: %v", act)
+	}
+
+	if act := bech32.LocateErrors(valid, bech32.Bech32m); act != nil {
+		t.Fatalf("Expected: nil, got: %v", act)
+	}
+}
+
+func TestError_Error(t *testing.T) {
+	tests := []struct {
+		err *bech32.Error
+		exp string
+	}{
+		{&bech32.Error{Err: bech32.ErrInvalidChecksum}, "invalid checksum"},
+		{&bech32.Error{Err: bech32.ErrInvalidCharacter, Positions: []int{5}}, "invalid character at position 5"},
+		{&bech32.Error{Err: bech32.ErrInvalidChecksum, Positions: []int{5, 9}}, "invalid checksum at positions 5, 9"},
+	}
+
+	for _, test := range tests {
+		if act := test.err.Error(); act != test.exp {
+			t.Fatalf("Expected: %s, got: %s", test.exp, act)
+		}
+	}
+}
@@ -29,9 +29,15 @@ import (
 // HeaderLength ...
 const HeaderLength = 167
 
+// HashLength ...
+const HashLength = 32
+
 // Block ...
 type Block []byte
 
+// BlockHash ...
+type BlockHash [HashLength]byte
+
 // NewBlock ...
 func NewBlock() Block {
 	b := make(Block, HeaderLength)
@@ -40,13 +46,37 @@ func NewBlock() Block {
 	return b
 }
 
+// NewBlockHash returns ErrInvalidLength unless b is exactly HashLength bytes long.
+func NewBlockHash(b []byte) (h BlockHash, err error) {
+	if len(b) != HashLength {
+		return h, ErrInvalidLength
+	}
+
+	copy(h[:], b)
+
+	return h, nil
+}
+
+// Bytes ...
+func (h BlockHash) Bytes() []byte {
+	b := make([]byte, HashLength)
+	copy(b, h[:])
+
+	return b
+}
+
 // Hash ...
 func (b Block) Hash() []byte {
-	h := sha256.Sum256(b[:HeaderLength])
+	h := b.Key()
 
 	return h[:]
 }
 
+// Key ...
+func (b Block) Key() BlockHash {
+	return sha256.Sum256(b[:HeaderLength])
+}
+
 // Version ...
 func (b Block) Version() uint8 {
 	return b[0]
@@ -154,7 +184,7 @@ func VerifyBlock(b []byte) error {
 // CalculateMerkleRoot ...
 func CalculateMerkleRoot(b Block) ([]byte, error) {
 	curCount := b.TxCount()
-	h := make([][32]byte, curCount)
+	h := make([][HashLength]byte, curCount)
 
 	if !checkUniqTx(b, h) {
 		return nil, ErrNonUniqueTx
@@ -167,23 +197,24 @@ func CalculateMerkleRoot(b Block) ([]byte, error) {
 	return h[0][:], nil
 }
 
-func checkUniqTx(b Block, h [][32]byte) bool {
-	u := make(map[[32]byte]struct{}, b.TxCount())
+func checkUniqTx(b Block, h [][HashLength]byte) bool {
+	u := make(map[TxHash]struct{}, b.TxCount())
 
 	for i, l := uint16(0), b.TxCount(); i < l; i++ {
-		h[i] = sha256.Sum256(b.Transaction(i))
-		if _, ok := u[h[i]]; ok {
+		k := (Transaction)(b.Transaction(i)).Key()
+		if _, ok := u[k]; ok {
 			return false
 		}
 
-		u[h[i]] = struct{}{}
+		u[k] = struct{}{}
+		h[i] = k
 	}
 
 	return true
 }
 
-func calculateLevel(h [][32]byte, nextCount int, prevCount int) {
-	t := make([]byte, 64)
+func calculateLevel(h [][HashLength]byte, nextCount int, prevCount int) {
+	t := make([]byte, HashLength*2)
 
 	for i := 0; i < nextCount; i++ {
 		k1 := i * 2 //nolint:gomnd
@@ -354,3 +354,49 @@ func TestBlock_Version(t *testing.T) {
 		t.Fatalf("Expected: %v, got: %v", exp, act)
 	}
 }
+
+func TestBlock_Key(t *testing.T) {
+	blk := libumi.NewBlock()
+	blk.SetTimestamp(uint32(time.Now().Unix()))
+
+	act, err := libumi.NewBlockHash(blk.Hash())
+	exp := blk.Key()
+
+	if err != nil || act != exp {
+		t.Fatalf("Expected: %x, got: %x %v", exp, act, err)
+	}
+
+	for _, n := range []int{0, libumi.HashLength - 1, libumi.HashLength + 1} {
+		if _, err := libumi.NewBlockHash(make([]byte, n)); !errors.Is(err, libumi.ErrInvalidLength) {
+			t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidLength, err)
+		}
+	}
+
+	if !bytes.Equal(exp.Bytes(), blk.Hash()) {
+		t.Fatalf("Expected: %x, got: %x", blk.Hash(), exp.Bytes())
+	}
+}
+
+func TestSignBlockWith(t *testing.T) {
+	_, sec, _ := ed25519.GenerateKey(rand.Reader)
+	s, _ := libumi.NewSigner(sec, "umi")
+
+	tx := libumi.NewTransaction().
+		SetSender(s.Address()).
+		SetRecipient(libumi.NewAddress().SetPrefix("aaa"))
+
+	libumi.SignTransactionWith(tx, s)
+
+	blk := libumi.NewBlock()
+	blk.SetPreviousBlockHash(bytes.Repeat([]byte{1}, 32))
+	blk.AppendTransaction(tx)
+
+	mrk, _ := libumi.CalculateMerkleRoot(blk)
+	blk.SetMerkleRootHash(mrk)
+
+	libumi.SignBlockWith(blk, s)
+
+	if err := libumi.VerifyBlock(blk); err != nil {
+		t.Fatalf("Expected: %v, got: %v", nil, err)
+	}
+}
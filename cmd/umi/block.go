@@ -0,0 +1,176 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"io"
+	"time"
+
+	"github.com/umitop/libumi"
+)
+
+type blockJSON struct {
+	Hash         string   `json:"hash"`
+	Version      uint8    `json:"version"`
+	PreviousHash string   `json:"previous_hash"`
+	MerkleRoot   string   `json:"merkle_root"`
+	Timestamp    string   `json:"timestamp"`
+	TxCount      uint16   `json:"tx_count"`
+	PublicKey    string   `json:"public_key"`
+	Signature    string   `json:"signature"`
+	Transactions []txJSON `json:"transactions"`
+}
+
+type merkleJSON struct {
+	MerkleRoot string `json:"merkle_root"`
+	Matches    *bool  `json:"matches,omitempty"`
+}
+
+func blockVerify(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	in := fs.String("in", "-", "block file")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	b, err := readInput(*in, stdin)
+	if err != nil {
+		return err
+	}
+
+	return writeVerify(stdout, libumi.DisassembleBlock(b))
+}
+
+func blockDecode(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	in := fs.String("in", "-", "block file")
+	dump := fs.Bool("dump", false, "print an annotated hex dump instead of JSON")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	b, err := readInput(*in, stdin)
+	if err != nil {
+		return err
+	}
+
+	if *dump {
+		_, err = io.WriteString(stdout, libumi.DisassembleBlock(b).String())
+
+		return err
+	}
+
+	blk, err := toBlock(b)
+	if err != nil {
+		return err
+	}
+
+	v := blockJSON{
+		Hash:         hex.EncodeToString(blk.Hash()),
+		Version:      blk.Version(),
+		PreviousHash: hex.EncodeToString(blk.PreviousBlockHash()),
+		MerkleRoot:   hex.EncodeToString(blk.MerkleRootHash()),
+		Timestamp:    time.Unix(int64(blk.Timestamp()), 0).UTC().Format(time.RFC3339),
+		TxCount:      blk.TxCount(),
+		PublicKey:    hex.EncodeToString(blk.PublicKey()),
+		Signature:    hex.EncodeToString(blk[103:libumi.HeaderLength]),
+		Transactions: []txJSON{},
+	}
+
+	for off := libumi.HeaderLength; off < len(blk); off += libumi.TxLength {
+		v.Transactions = append(v.Transactions, newTxJSON(libumi.Transaction(blk[off:off+libumi.TxLength])))
+	}
+
+	return writeJSON(stdout, v)
+}
+
+func merkle(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	in := fs.String("in", "-", "block file")
+	txs := fs.Bool("txs", false, "input is a list of transactions instead of a block")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	b, err := readInput(*in, stdin)
+	if err != nil {
+		return err
+	}
+
+	if *txs {
+		b, err = txsToBlock(b)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	blk, err := toBlock(b)
+	if err != nil {
+		return err
+	}
+
+	mrk, err := libumi.CalculateMerkleRoot(blk)
+	if err != nil {
+		return err
+	}
+
+	v := merkleJSON{MerkleRoot: hex.EncodeToString(mrk)}
+
+	if !*txs {
+		matches := bytes.Equal(mrk, blk.MerkleRootHash())
+		v.Matches = &matches
+	}
+
+	return writeJSON(stdout, v)
+}
+
+// toBlock checks that the data is a header followed by as many transactions
+// as the header says.
+func toBlock(b []byte) (libumi.Block, error) {
+	if len(b) < libumi.HeaderLength || (len(b)-libumi.HeaderLength)%libumi.TxLength != 0 {
+		return nil, libumi.ErrInvalidLength
+	}
+
+	blk := libumi.Block(b)
+	if int(blk.TxCount()) != (len(b)-libumi.HeaderLength)/libumi.TxLength {
+		return nil, libumi.ErrInvalidLength
+	}
+
+	return blk, nil
+}
+
+func txsToBlock(b []byte) (libumi.Block, error) {
+	if len(b) == 0 || len(b)%libumi.TxLength != 0 {
+		return nil, libumi.ErrInvalidLength
+	}
+
+	blk := libumi.NewBlock()
+
+	for off := 0; off < len(b); off += libumi.TxLength {
+		blk.AppendTransaction(b[off : off+libumi.TxLength])
+	}
+
+	return blk, nil
+}
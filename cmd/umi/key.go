@@ -0,0 +1,126 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"io"
+	"io/ioutil"
+
+	"github.com/umitop/libumi"
+)
+
+type keyJSON struct {
+	Address    string `json:"address"`
+	Prefix     string `json:"prefix"`
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key,omitempty"`
+}
+
+func keygen(fs *flag.FlagSet, args []string, _ io.Reader, stdout io.Writer) error {
+	prefix := fs.String("prefix", "umi", "address prefix")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	pub, sec, adr, err := libumi.GenerateKey(rand.Reader, *prefix)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(stdout, keyJSON{
+		Address:    adr.Bech32(),
+		Prefix:     adr.Prefix(),
+		PublicKey:  hex.EncodeToString(pub),
+		PrivateKey: hex.EncodeToString(sec),
+	})
+}
+
+func address(fs *flag.FlagSet, args []string, _ io.Reader, stdout io.Writer) error {
+	prefix := fs.String("prefix", "umi", "address prefix for a public key")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+
+		return errUsage
+	}
+
+	adr, err := libumi.NewAddressFromBech32(fs.Arg(0))
+	if err != nil {
+		pub, e := hex.DecodeString(fs.Arg(0))
+		if e != nil {
+			return err
+		}
+
+		if adr, err = libumi.AddressFromPublicKey(pub, *prefix); err != nil {
+			return err
+		}
+	}
+
+	return writeJSON(stdout, keyJSON{
+		Address:   adr.Bech32(),
+		Prefix:    adr.Prefix(),
+		PublicKey: hex.EncodeToString(adr.PublicKey()),
+	})
+}
+
+// readKey reads a PKCS#8 or OpenSSH PEM key, or a hex or base64 encoded seed
+// or private key.
+func readKey(name string) (ed25519.PrivateKey, error) {
+	if name == "" {
+		return nil, errors.New("missing -key")
+	}
+
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("-----")) {
+		return libumi.ParsePrivateKeyPEM(b)
+	}
+
+	b = decodeBinary(b)
+
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		sec := ed25519.PrivateKey(b)
+		if _, err := libumi.AddressFromPrivateKey(sec, "umi"); err != nil {
+			return nil, err
+		}
+
+		return sec, nil
+	default:
+		return nil, libumi.ErrInvalidKey
+	}
+}
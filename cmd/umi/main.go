@@ -0,0 +1,164 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command umi works with UMI keys, addresses, transactions and blocks.
+//
+// Binary input is read from the file given with -in, or from stdin, and may
+// be hex, base64 or raw bytes. Results are written to stdout as JSON.
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+const usage = `usage: umi <command> [flags]
+
+commands:
+  keygen        generate a key pair
+  address       convert a public key to bech32 and back
+  tx build      build an unsigned transaction
+  tx sign       sign a transaction
+  tx verify     verify a transaction
+  tx decode     decode a transaction
+  block verify  verify a block
+  block decode  decode a block
+  merkle        calculate the merkle root of a block
+  payout        sign basic transactions for a CSV of recipients and amounts
+`
+
+var errUsage = errors.New("invalid usage")
+
+type command func(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	name, cmd, args := lookup(args)
+	if cmd == nil {
+		fmt.Fprint(stderr, usage)
+
+		return 2
+	}
+
+	fs := flag.NewFlagSet("umi "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	if err := cmd(fs, args, stdin, stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+
+		fmt.Fprintf(stderr, "umi: %v\n", err)
+
+		return 1
+	}
+
+	return 0
+}
+
+func lookup(args []string) (string, command, []string) {
+	commands := map[string]command{
+		"keygen":       keygen,
+		"address":      address,
+		"tx build":     txBuild,
+		"tx sign":      txSign,
+		"tx verify":    txVerify,
+		"tx decode":    txDecode,
+		"block verify": blockVerify,
+		"block decode": blockDecode,
+		"merkle":       merkle,
+		"payout":       payout,
+	}
+
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			return args[0], cmd, args[1:]
+		}
+	}
+
+	if len(args) > 1 {
+		if cmd, ok := commands[args[0]+" "+args[1]]; ok {
+			return args[0] + " " + args[1], cmd, args[2:]
+		}
+	}
+
+	return "", nil, nil
+}
+
+// parseFlags reports flag errors as usage errors, flag has already printed
+// them along with the usage.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	return nil
+}
+
+// readInput reads the named file, or stdin for "" and "-", and decodes hex or
+// base64 text. Anything else is returned as raw bytes.
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	b, err := readFile(name, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBinary(b), nil
+}
+
+func readFile(name string, stdin io.Reader) ([]byte, error) {
+	if name == "" || name == "-" {
+		return ioutil.ReadAll(stdin)
+	}
+
+	return ioutil.ReadFile(name)
+}
+
+func decodeBinary(b []byte) []byte {
+	s := string(bytes.TrimSpace(b))
+
+	if d, err := hex.DecodeString(s); err == nil {
+		return d
+	}
+
+	if d, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return d
+	}
+
+	return b
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func runJSON(t *testing.T, stdin string, v interface{}, args ...string) int {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	if v != nil && stdout.Len() > 0 {
+		if err := json.Unmarshal(stdout.Bytes(), v); err != nil {
+			t.Fatalf("Expected: nil, got: %v %s", err, stdout.String())
+		}
+	}
+
+	return code
+}
+
+func TestRun(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "umi")
+	defer os.RemoveAll(dir)
+
+	var key, rcp keyJSON
+
+	if code := runJSON(t, "", &key, "keygen"); code != 0 {
+		t.Fatalf("Expected: 0, got: %d", code)
+	}
+
+	_ = runJSON(t, "", &rcp, "keygen", "-prefix", "aaa")
+
+	keyFile := filepath.Join(dir, "key")
+	_ = ioutil.WriteFile(keyFile, []byte(key.PrivateKey), 0o600)
+
+	var unsigned, signed txJSON
+
+	_ = runJSON(t, "", &unsigned, "tx", "build", "-sender", key.Address, "-recipient", rcp.Address, "-amount", "1.5")
+	if unsigned.Amount != 1_50 || unsigned.Recipient != rcp.Address {
+		t.Fatalf("Expected: 1.50 %s, got: %s %s", rcp.Address, unsigned.Amount, unsigned.Recipient)
+	}
+
+	if code := runJSON(t, unsigned.Hex, &signed, "tx", "sign", "-key", keyFile); code != 0 {
+		t.Fatalf("Expected: 0, got: %d", code)
+	}
+
+	if signed.Nonce != unsigned.Nonce {
+		t.Fatalf("Expected: %d, got: %d", unsigned.Nonce, signed.Nonce)
+	}
+
+	var res verifyJSON
+
+	if code := runJSON(t, signed.Hex, &res, "tx", "verify"); code != 0 || !res.Valid {
+		t.Fatalf("Expected: 0 true, got: %d %v", code, res.Valid)
+	}
+
+	if code := runJSON(t, unsigned.Hex, &res, "tx", "verify"); code != 1 || res.Field != "signature" {
+		t.Fatalf("Expected: 1 signature, got: %d %s", code, res.Field)
+	}
+}
+
+func TestRunBlock(t *testing.T) {
+	blk := libumi.NewBlock()
+	blk.AppendTransaction(libumi.NewTransaction().SetSender(libumi.NewAddress()))
+	mrk, _ := libumi.CalculateMerkleRoot(blk)
+	blk.SetMerkleRootHash(mrk)
+
+	var dec blockJSON
+
+	if code := runJSON(t, hex.EncodeToString(blk), &dec, "block", "decode"); code != 0 {
+		t.Fatalf("Expected: 0, got: %d", code)
+	}
+
+	if dec.TxCount != 1 || len(dec.Transactions) != 1 || dec.MerkleRoot != hex.EncodeToString(mrk) {
+		t.Fatalf("Expected: 1 %x, got: %d %s", mrk, dec.TxCount, dec.MerkleRoot)
+	}
+
+	var m merkleJSON
+
+	_ = runJSON(t, hex.EncodeToString(blk), &m, "merkle")
+	if m.Matches == nil || !*m.Matches {
+		t.Fatalf("Expected: true, got: %v", m.Matches)
+	}
+
+	var res verifyJSON
+
+	if code := runJSON(t, hex.EncodeToString(blk), &res, "block", "verify"); code != 1 || res.Valid {
+		t.Fatalf("Expected: 1 false, got: %d %v", code, res.Valid)
+	}
+}
+
+func TestRunPayout(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "umi")
+	defer os.RemoveAll(dir)
+
+	var key, a, b keyJSON
+
+	_ = runJSON(t, "", &key, "keygen")
+	_ = runJSON(t, "", &a, "keygen", "-prefix", "aaa")
+	_ = runJSON(t, "", &b, "keygen", "-prefix", "bbb")
+
+	keyFile := filepath.Join(dir, "key")
+	_ = ioutil.WriteFile(keyFile, []byte(key.PrivateKey), 0o600)
+
+	csv := "address,amount\n" + a.Address + ",1.00\n" + b.Address + ",2.50\n"
+
+	var res payoutJSON
+
+	if code := runJSON(t, csv, &res, "payout", "-key", keyFile, "-nonce", "10"); code != 0 {
+		t.Fatalf("Expected: 0, got: %d", code)
+	}
+
+	if res.Payments != 2 || res.Total != 3_50 || res.Transactions[1].Nonce != 11 {
+		t.Fatalf("Expected: 2 3.50 11, got: %d %s %d", res.Payments, res.Total, res.Transactions[1].Nonce)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"payout", "-dry-run", "-sender", a.Address}, strings.NewReader(csv), &stdout, &stderr); code != 1 {
+		t.Fatalf("Expected: 1 for a payment to the sender, got: %d", code)
+	}
+
+	if code := run([]string{"payout", "-dry-run", "-sender", key.Address}, strings.NewReader(csv), &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected: 0, got: %d", code)
+	}
+
+	if !strings.Contains(stdout.String(), "Total:    3.50 UMI") {
+		t.Fatalf("Expected: report, got: %s", stdout.String())
+	}
+
+	if code := runJSON(t, csv+a.Address+",1\n", nil, "payout", "-dry-run", "-key", keyFile); code != 1 {
+		t.Fatalf("Expected: 1, got: %d", code)
+	}
+
+	if code := runJSON(t, csv, nil, "payout", "-key", keyFile, "-sender", a.Address); code != 1 {
+		t.Fatalf("Expected: 1, got: %d", code)
+	}
+
+	if code := runJSON(t, csv, nil, "payout", "-dry-run"); code != 2 {
+		t.Fatalf("Expected: 2, got: %d", code)
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	tests := [][]string{nil, {"tx"}, {"tx", "unknown"}, {"address"}, {"keygen", "-unknown"}}
+
+	for _, args := range tests {
+		if code := runJSON(t, "", nil, args...); code != 2 {
+			t.Fatalf("Expected: 2, got: %d", code)
+		}
+	}
+}
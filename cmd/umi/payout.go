@@ -0,0 +1,123 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"time"
+
+	"github.com/umitop/libumi"
+)
+
+type payoutJSON struct {
+	Payments     int           `json:"payments"`
+	Total        libumi.Amount `json:"total"`
+	Transactions []txJSON      `json:"transactions"`
+}
+
+func payout(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	in := fs.String("in", "-", "CSV file with address,amount rows")
+	key := fs.String("key", "", "private key file")
+	prefix := fs.String("prefix", "umi", "sender address prefix")
+	from := fs.String("sender", "", "sender address, instead of -key with -dry-run")
+	nonce := fs.Uint64("nonce", uint64(time.Now().UnixNano()), "nonce of the first transaction")
+	dryRun := fs.Bool("dry-run", false, "validate and print a report without signing")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *key == "" && (!*dryRun || *from == "") {
+		fs.Usage()
+
+		return errUsage
+	}
+
+	b, err := readFile(*in, stdin)
+	if err != nil {
+		return err
+	}
+
+	var signer libumi.Signer
+
+	if *key != "" {
+		sec, err := readKey(*key)
+		if err != nil {
+			return err
+		}
+
+		if signer, err = libumi.NewSigner(sec, *prefix); err != nil {
+			return err
+		}
+
+		defer signer.Wipe()
+	}
+
+	sender, err := payoutSender(*from, signer)
+	if err != nil {
+		return err
+	}
+
+	p, err := libumi.ParsePayoutCSV(bytes.NewReader(b), sender)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		_, err = io.WriteString(stdout, p.Report())
+
+		return err
+	}
+
+	txs, err := p.Sign(signer, *nonce)
+	if err != nil {
+		return err
+	}
+
+	v := payoutJSON{Payments: len(txs), Total: p.Total, Transactions: make([]txJSON, len(txs))}
+
+	for i, tx := range txs {
+		v.Transactions[i] = newTxJSON(tx)
+	}
+
+	return writeJSON(stdout, v)
+}
+
+// payoutSender returns the -sender address, which must belong to the key
+// if both are given.
+func payoutSender(from string, signer libumi.Signer) (libumi.Address, error) {
+	if from == "" {
+		return signer.Address(), nil
+	}
+
+	adr, err := libumi.NewAddressFromBech32(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if signer != nil && adr.Key() != signer.Address().Key() {
+		return nil, libumi.ErrInvalidSender
+	}
+
+	return adr, nil
+}
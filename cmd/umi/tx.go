@@ -0,0 +1,272 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/umitop/libumi"
+)
+
+var txTypes = map[string]uint8{
+	"genesis":                libumi.Genesis,
+	"basic":                  libumi.Basic,
+	"create_structure":       libumi.CreateStructure,
+	"update_structure":       libumi.UpdateStructure,
+	"update_profit_address":  libumi.UpdateProfitAddress,
+	"update_fee_address":     libumi.UpdateFeeAddress,
+	"create_transit_address": libumi.CreateTransitAddress,
+	"delete_transit_address": libumi.DeleteTransitAddress,
+}
+
+type txJSON struct {
+	Hash string `json:"hash"`
+	libumi.EnvelopeDetails
+	Signature string   `json:"signature"`
+	Hex       string   `json:"hex"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+type verifyJSON struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+	Field string `json:"field,omitempty"`
+}
+
+func newTxJSON(tx libumi.Transaction) txJSON {
+	v := txJSON{
+		Hash:      hex.EncodeToString(tx.Hash()),
+		Signature: hex.EncodeToString(tx.Signature()),
+		Hex:       hex.EncodeToString(tx),
+		Warnings:  libumi.TransactionWarnings(tx),
+	}
+
+	if env, err := libumi.NewEnvelope(tx); err == nil {
+		v.EnvelopeDetails = env.EnvelopeDetails
+	}
+
+	return v
+}
+
+func txBuild(fs *flag.FlagSet, args []string, _ io.Reader, stdout io.Writer) error {
+	var (
+		typ       = fs.String("type", "basic", "transaction type")
+		sender    = fs.String("sender", "", "sender address")
+		recipient = fs.String("recipient", "", "recipient address")
+		amount    = fs.String("amount", "0", "amount in UMI")
+		prefix    = fs.String("prefix", "", "structure prefix")
+		profit    = fs.String("profit", "0%", "structure profit percent")
+		fee       = fs.String("fee", "0%", "structure fee percent")
+		name      = fs.String("name", "", "structure name")
+		nonce     = fs.Uint64("nonce", uint64(time.Now().UnixNano()), "nonce")
+	)
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	ver, ok := txTypes[*typ]
+	if !ok {
+		return fmt.Errorf("unknown transaction type %q", *typ)
+	}
+
+	snd, err := libumi.NewAddressFromBech32(*sender)
+	if err != nil {
+		return fmt.Errorf("sender: %w", err)
+	}
+
+	tx := libumi.NewTransaction().SetVersion(ver).SetSender(snd).SetNonce(*nonce)
+
+	switch ver {
+	case libumi.CreateStructure, libumi.UpdateStructure:
+		err = buildStructure(tx, *prefix, *profit, *fee, *name)
+	default:
+		err = buildTransfer(tx, *recipient, *amount)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(stdout, newTxJSON(tx))
+}
+
+func buildTransfer(tx libumi.Transaction, recipient, amount string) error {
+	rcp, err := libumi.NewAddressFromBech32(recipient)
+	if err != nil {
+		return fmt.Errorf("recipient: %w", err)
+	}
+
+	amt, err := libumi.ParseAmount(amount)
+	if err != nil {
+		return fmt.Errorf("amount: %w", err)
+	}
+
+	tx.SetRecipient(rcp).SetAmount(amt)
+
+	return nil
+}
+
+func buildStructure(tx libumi.Transaction, prefix, profit, fee, name string) error {
+	pp, err := libumi.ParsePercent(profit)
+	if err != nil {
+		return fmt.Errorf("profit: %w", err)
+	}
+
+	fp, err := libumi.ParsePercent(fee)
+	if err != nil {
+		return fmt.Errorf("fee: %w", err)
+	}
+
+	if len(name) > 35 {
+		return libumi.ErrInvalidName
+	}
+
+	tx.SetPrefix(prefix).SetProfit(pp).SetFee(fp).SetName(name)
+
+	return nil
+}
+
+// txSign signs the transaction as is, keeping the nonce it was built with.
+func txSign(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	in := fs.String("in", "-", "transaction file")
+	key := fs.String("key", "", "private key file")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	tx, err := readTransaction(*in, stdin)
+	if err != nil {
+		return err
+	}
+
+	sec, err := readKey(*key)
+	if err != nil {
+		return err
+	}
+
+	signer, err := libumi.NewSigner(sec, tx.Sender().Prefix())
+	if err != nil {
+		return err
+	}
+
+	defer signer.Wipe()
+
+	env, err := libumi.NewEnvelope(tx)
+	if err != nil {
+		return err
+	}
+
+	if err = libumi.SignEnvelope(env, signer); err != nil {
+		return err
+	}
+
+	if tx, err = env.Transaction(); err != nil {
+		return err
+	}
+
+	return writeJSON(stdout, newTxJSON(tx))
+}
+
+func txVerify(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	in := fs.String("in", "-", "transaction file")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	b, err := readInput(*in, stdin)
+	if err != nil {
+		return err
+	}
+
+	return writeVerify(stdout, libumi.DisassembleTransaction(b))
+}
+
+func txDecode(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	in := fs.String("in", "-", "transaction file")
+	dump := fs.Bool("dump", false, "print an annotated hex dump instead of JSON")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *dump {
+		b, err := readInput(*in, stdin)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(stdout, libumi.DisassembleTransaction(b).String())
+
+		return err
+	}
+
+	tx, err := readTransaction(*in, stdin)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(stdout, newTxJSON(tx))
+}
+
+func readTransaction(name string, stdin io.Reader) (libumi.Transaction, error) {
+	b, err := readInput(name, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) != libumi.TxLength {
+		return nil, libumi.ErrInvalidLength
+	}
+
+	return b, nil
+}
+
+// writeVerify writes the verification result and returns the error so that
+// the exit status reflects it.
+func writeVerify(w io.Writer, d libumi.Disassembly) error {
+	v := verifyJSON{Valid: true}
+
+	for _, f := range d {
+		if f.Err != nil {
+			v = verifyJSON{Error: f.Err.Error(), Field: f.Name}
+
+			break
+		}
+	}
+
+	if err := writeJSON(w, v); err != nil {
+		return err
+	}
+
+	if !v.Valid {
+		return errors.New(v.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,349 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dumpWidth = 16
+
+// Field is a named byte range of a transaction or a block.
+type Field struct {
+	Name   string
+	Offset int
+	Bytes  []byte
+	Value  string
+	Err    error
+}
+
+// Disassembly ...
+type Disassembly []Field
+
+type fieldLayout struct {
+	name     string
+	from, to int
+	decode   func([]byte) string
+}
+
+var (
+	txCommonLayout = []fieldLayout{
+		{"version", 0, 1, decodeTxVersion},
+		{"sender", 1, 35, decodeAddress},
+	}
+	txTransferLayout = []fieldLayout{
+		{"recipient", 35, 69, decodeAddress},
+		{"value", 69, 77, decodeValue},
+	}
+	txStructureLayout = []fieldLayout{
+		{"prefix", 35, 37, func(b []byte) string { return versionToPrefix(binary.BigEndian.Uint16(b)) }},
+		{"profit", 37, 39, decodePercent},
+		{"fee", 39, 41, decodePercent},
+		{"name length", 41, 42, func(b []byte) string { return strconv.Itoa(int(b[0])) }},
+		{"name", 42, 77, decodeName},
+	}
+	txTailLayout = []fieldLayout{
+		{"nonce", 77, 85, func(b []byte) string { return strconv.FormatUint(binary.BigEndian.Uint64(b), 10) }},
+		{"signature", 85, 149, nil},
+		{"reserved", 149, 150, nil},
+	}
+	blockLayout = []fieldLayout{
+		{"version", 0, 1, decodeBlockVersion},
+		{"previous hash", 1, 33, nil},
+		{"merkle root", 33, 65, nil},
+		{"timestamp", 65, 69, decodeTimestamp},
+		{"tx count", 69, 71, func(b []byte) string { return strconv.Itoa(int(binary.BigEndian.Uint16(b))) }},
+		{"public key", 71, 103, nil},
+		{"signature", 103, 167, nil},
+	}
+)
+
+var txErrorFields = map[error]string{
+	ErrInvalidVersion:       "version",
+	ErrInvalidSender:        "sender",
+	ErrInvalidRecipient:     "recipient",
+	ErrInvalidPrefix:        "prefix",
+	ErrInvalidProfitPercent: "profit",
+	ErrInvalidFeePercent:    "fee",
+	ErrInvalidName:          "name",
+	ErrInvalidSignature:     "signature",
+}
+
+var blockErrorFields = map[error]string{
+	ErrInvalidLength:    "tx count",
+	ErrInvalidVersion:   "version",
+	ErrInvalidSignature: "signature",
+	ErrInvalidPrevHash:  "previous hash",
+	ErrInvalidMerkle:    "merkle root",
+}
+
+// DisassembleTransaction splits the transaction into fields and marks the
+// field rejected by VerifyTransaction, if any.
+func DisassembleTransaction(t []byte) Disassembly {
+	d := disassembleTx(t, 0, "")
+
+	if len(t) > TxLength {
+		d = append(d, Field{Name: "trailing", Offset: TxLength, Bytes: t[TxLength:]})
+	}
+
+	d.markTx(t, "", VerifyTransaction(t))
+
+	return d
+}
+
+// DisassembleBlock splits the block header and every transaction into fields
+// and marks the fields rejected by VerifyBlock, if any.
+func DisassembleBlock(b []byte) Disassembly {
+	d := disassemble(b, 0, "", blockLayout)
+
+	for i, off := 0, HeaderLength; off < len(b); i, off = i+1, off+TxLength {
+		end := off + TxLength
+		if end > len(b) {
+			end = len(b)
+		}
+
+		tx := b[off:end]
+		hdr := Field{Name: fmt.Sprintf("tx[%d]", i), Offset: off}
+
+		if len(tx) == TxLength {
+			hdr.Value = hex.EncodeToString(Transaction(tx).Hash())
+		}
+
+		d = append(d, hdr)
+		d = append(d, disassembleTx(tx, off, hdr.Name+".")...)
+	}
+
+	d.markBlock(b, VerifyBlock(b))
+
+	return d
+}
+
+// Err returns the first marked error.
+func (d Disassembly) Err() error {
+	for _, f := range d {
+		if f.Err != nil {
+			return f.Err
+		}
+	}
+
+	return nil
+}
+
+// String renders an annotated hex dump with one field per line, or several
+// lines for fields longer than 16 bytes. Rejected fields are marked with '>'.
+func (d Disassembly) String() string {
+	var s strings.Builder
+
+	for _, f := range d {
+		mark := " "
+		if f.Err != nil {
+			mark = ">"
+		}
+
+		for i := 0; i == 0 || i < len(f.Bytes); i += dumpWidth {
+			end := i + dumpWidth
+			if end > len(f.Bytes) {
+				end = len(f.Bytes)
+			}
+
+			line := fmt.Sprintf("%s %04x  %-47s", mark, f.Offset+i, dumpHex(f.Bytes[i:end]))
+
+			if i == 0 {
+				line = fmt.Sprintf("%s  %-20s %s", line, f.Name, f.Value)
+
+				if f.Err != nil {
+					line += "  ! " + f.Err.Error()
+				}
+			}
+
+			s.WriteString(strings.TrimRight(line, " "))
+			s.WriteString("\n")
+		}
+	}
+
+	return s.String()
+}
+
+func (d Disassembly) mark(name string, err error) bool {
+	for i := range d {
+		if d[i].Name == name {
+			d[i].Err = err
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d Disassembly) markTx(t []byte, prefix string, err error) {
+	if err == nil {
+		return
+	}
+
+	if errors.Is(err, ErrInvalidLength) {
+		d.markLength(err)
+
+		return
+	}
+
+	name := txErrorFields[err]
+
+	if errors.Is(err, ErrInvalidName) && t[41] > maxNameLength {
+		name = "name length"
+	}
+
+	if !d.mark(prefix+name, err) {
+		d.mark(prefix+"version", err)
+	}
+}
+
+// markLength marks the first missing or truncated field, or trailing bytes.
+func (d Disassembly) markLength(err error) bool {
+	for i := range d {
+		if d[i].Name == "trailing" || d[i].Value == "missing" || d[i].Value == "truncated" {
+			d[i].Err = err
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d Disassembly) markBlock(b []byte, err error) {
+	if err == nil {
+		return
+	}
+
+	if errors.Is(err, ErrInvalidLength) && d.markLength(err) {
+		return
+	}
+
+	if name, ok := blockErrorFields[err]; ok {
+		d.mark(name, err)
+
+		return
+	}
+
+	seen := make(map[TxHash]struct{})
+
+	for i, off := 0, HeaderLength; off+TxLength <= len(b); i, off = i+1, off+TxLength {
+		tx := Transaction(b[off : off+TxLength])
+		prefix := fmt.Sprintf("tx[%d]", i)
+
+		switch {
+		case errors.Is(err, ErrNonUniqueTx):
+			if _, ok := seen[tx.Key()]; ok {
+				d.mark(prefix, err)
+			}
+
+			seen[tx.Key()] = struct{}{}
+		case (b[0] == Genesis) != (tx.Version() == Genesis):
+			d.mark(prefix+".version", err)
+		default:
+			d.markTx(tx, prefix+".", VerifyTransaction(tx))
+		}
+	}
+}
+
+func disassembleTx(t []byte, off int, prefix string) Disassembly {
+	layout := append(append([]fieldLayout{}, txCommonLayout...), txTransferLayout...)
+
+	if len(t) > 0 && (t[0] == CreateStructure || t[0] == UpdateStructure) {
+		layout = append(layout[:len(txCommonLayout)], txStructureLayout...)
+	}
+
+	return disassemble(t, off, prefix, append(layout, txTailLayout...))
+}
+
+func disassemble(b []byte, off int, prefix string, layout []fieldLayout) (d Disassembly) {
+	for _, l := range layout {
+		if l.from >= len(b) {
+			d = append(d, Field{Name: prefix + l.name, Offset: off + l.from, Value: "missing"})
+
+			continue
+		}
+
+		f := Field{Name: prefix + l.name, Offset: off + l.from}
+
+		switch {
+		case l.to > len(b):
+			f.Bytes, f.Value = b[l.from:], "truncated"
+		case l.decode != nil:
+			f.Bytes, f.Value = b[l.from:l.to], l.decode(b[l.from:l.to])
+		default:
+			f.Bytes = b[l.from:l.to]
+		}
+
+		d = append(d, f)
+	}
+
+	return d
+}
+
+func dumpHex(b []byte) string {
+	s := make([]string, len(b))
+
+	for i := range b {
+		s[i] = hex.EncodeToString(b[i : i+1])
+	}
+
+	return strings.Join(s, " ")
+}
+
+func decodeTxVersion(b []byte) string {
+	return fmt.Sprintf("%d (%s)", b[0], txTypeName(b[0]))
+}
+
+func decodeBlockVersion(b []byte) string {
+	if b[0] > Basic {
+		return fmt.Sprintf("%d (unknown)", b[0])
+	}
+
+	return fmt.Sprintf("%d (%s)", b[0], txTypeNames[b[0]])
+}
+
+func decodeAddress(b []byte) string {
+	return Address(b).Bech32()
+}
+
+func decodeValue(b []byte) string {
+	return Amount(binary.BigEndian.Uint64(b)).String() + " UMI"
+}
+
+func decodePercent(b []byte) string {
+	return Percent(binary.BigEndian.Uint16(b)).String()
+}
+
+func decodeName(b []byte) string {
+	return strconv.QuoteToASCII(strings.TrimRight(string(b), "\x00"))
+}
+
+func decodeTimestamp(b []byte) string {
+	return time.Unix(int64(binary.BigEndian.Uint32(b)), 0).UTC().Format(time.RFC3339)
+}
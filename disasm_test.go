@@ -0,0 +1,164 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func findField(d libumi.Disassembly, name string) libumi.Field {
+	for _, f := range d {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return libumi.Field{}
+}
+
+func TestDisassembleTransaction(t *testing.T) {
+	pub, sec, _ := ed25519.GenerateKey(rand.Reader)
+
+	tx := libumi.NewTransaction().
+		SetSender(libumi.NewAddress().SetPublicKey(pub)).
+		SetRecipient(libumi.NewAddress().SetPrefix("aaa")).
+		SetAmount(12_34)
+
+	libumi.SignTransaction(tx, sec)
+
+	d := libumi.DisassembleTransaction(tx)
+	if err := d.Err(); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if f := findField(d, "value"); f.Offset != 69 || len(f.Bytes) != 8 || f.Value != "12.34 UMI" {
+		t.Fatalf("Expected: 69 8 12.34 UMI, got: %d %d %s", f.Offset, len(f.Bytes), f.Value)
+	}
+
+	tx[100] ^= 1
+	d = libumi.DisassembleTransaction(tx)
+
+	if f := findField(d, "signature"); !errors.Is(f.Err, libumi.ErrInvalidSignature) || f.Offset != 85 {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidSignature, f.Err)
+	}
+
+	if act := d.String(); !strings.Contains(act, "> 0055  ") || !strings.Contains(act, "! invalid signature") {
+		t.Fatalf("Expected: highlighted signature, got: %s", act)
+	}
+}
+
+func TestDisassembleTransactionInvalid(t *testing.T) {
+	name := libumi.NewTransaction().SetVersion(libumi.CreateStructure).SetSender(libumi.NewAddress()).
+		SetPrefix("aaa").SetProfit(1_00)
+	name[41] = 36
+
+	profit := libumi.NewTransaction().SetVersion(libumi.CreateStructure).SetSender(libumi.NewAddress()).
+		SetPrefix("aaa")
+
+	tests := []struct {
+		tx    []byte
+		field string
+		err   error
+	}{
+		{libumi.NewTransaction().SetVersion(99), "version", libumi.ErrInvalidVersion},
+		{profit, "profit", libumi.ErrInvalidProfitPercent},
+		{name, "name length", libumi.ErrInvalidName},
+		{libumi.NewTransaction()[:100], "signature", libumi.ErrInvalidLength},
+		{append(libumi.NewTransaction(), 1), "trailing", libumi.ErrInvalidLength},
+		{nil, "version", libumi.ErrInvalidLength},
+	}
+
+	for _, test := range tests {
+		if f := findField(libumi.DisassembleTransaction(test.tx), test.field); !errors.Is(f.Err, test.err) {
+			t.Fatalf("Expected: %s %v, got: %v", test.field, test.err, f.Err)
+		}
+	}
+}
+
+func TestDisassembleBlock(t *testing.T) {
+	pub, sec, _ := ed25519.GenerateKey(rand.Reader)
+
+	tx := libumi.NewTransaction().
+		SetSender(libumi.NewAddress().SetPublicKey(pub)).
+		SetRecipient(libumi.NewAddress().SetPrefix("aaa"))
+
+	libumi.SignTransaction(tx, sec)
+
+	bad := libumi.NewTransaction().SetSender(libumi.NewAddress()).SetRecipient(libumi.NewAddress().SetPrefix("aaa"))
+
+	blk := libumi.NewBlock()
+	blk.AppendTransaction(tx)
+	blk.AppendTransaction(bad)
+	blk.SetPreviousBlockHash(blk.Hash())
+
+	mrk, _ := libumi.CalculateMerkleRoot(blk)
+	blk.SetMerkleRootHash(mrk)
+	libumi.SignBlock(blk, sec)
+
+	d := libumi.DisassembleBlock(blk)
+
+	if f := findField(d, "tx[1].signature"); !errors.Is(f.Err, libumi.ErrInvalidSignature) || f.Offset != 167+150+85 {
+		t.Fatalf("Expected: %v at %d, got: %v at %d", libumi.ErrInvalidSignature, 167+150+85, f.Err, f.Offset)
+	}
+
+	if f := findField(d, "tx[0].signature"); f.Err != nil {
+		t.Fatalf("Expected: nil, got: %v", f.Err)
+	}
+
+	if f := findField(d, "tx count"); f.Value != "2" {
+		t.Fatalf("Expected: 2, got: %s", f.Value)
+	}
+}
+
+func TestDisassembleBlockDuplicate(t *testing.T) {
+	pub, sec, _ := ed25519.GenerateKey(rand.Reader)
+
+	tx := libumi.NewTransaction().
+		SetSender(libumi.NewAddress().SetPublicKey(pub)).
+		SetRecipient(libumi.NewAddress().SetPrefix("aaa"))
+
+	libumi.SignTransaction(tx, sec)
+
+	blk := libumi.NewBlock()
+	blk.AppendTransaction(tx)
+	blk.AppendTransaction(tx)
+	blk.SetPreviousBlockHash(blk.Hash())
+	libumi.SignBlock(blk, sec)
+
+	d := libumi.DisassembleBlock(blk)
+
+	if f := findField(d, "tx[1]"); !errors.Is(f.Err, libumi.ErrNonUniqueTx) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrNonUniqueTx, f.Err)
+	}
+
+	blk[0] = 9
+	libumi.SignBlock(blk, sec)
+
+	if f := findField(libumi.DisassembleBlock(blk), "version"); !errors.Is(f.Err, libumi.ErrInvalidVersion) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidVersion, f.Err)
+	}
+}
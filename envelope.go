@@ -0,0 +1,219 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	envelopeVersion = 1
+	payloadLength   = 85
+)
+
+// Errors.
+var (
+	ErrInvalidEnvelope  = errors.New("invalid envelope")
+	ErrEnvelopeMismatch = errors.New("envelope does not match payload")
+	ErrEnvelopeUnsigned = errors.New("envelope is not signed")
+)
+
+var txTypeNames = [...]string{
+	Genesis:              "genesis",
+	Basic:                "basic",
+	CreateStructure:      "create_structure",
+	UpdateStructure:      "update_structure",
+	UpdateProfitAddress:  "update_profit_address",
+	UpdateFeeAddress:     "update_fee_address",
+	CreateTransitAddress: "create_transit_address",
+	DeleteTransitAddress: "delete_transit_address",
+}
+
+// Envelope carries the signable part of a transaction between an online and
+// an offline machine. The readable fields are derived from Payload and are
+// checked against it before signing and before combining, so an envelope
+// edited to show something other than what is signed is rejected.
+type Envelope struct {
+	Payload   []byte `json:"-"`
+	Signature []byte `json:"-"`
+	EnvelopeDetails
+}
+
+// EnvelopeDetails ...
+type EnvelopeDetails struct {
+	Type      string  `json:"type"`
+	Sender    string  `json:"sender"`
+	Recipient string  `json:"recipient,omitempty"`
+	Amount    Amount  `json:"amount,omitempty"`
+	Prefix    string  `json:"prefix,omitempty"`
+	Profit    Percent `json:"profit,omitempty"`
+	Fee       Percent `json:"fee,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Nonce     uint64  `json:"nonce"`
+}
+
+type envelopeJSON struct {
+	Version   int    `json:"version"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature,omitempty"`
+	*envelopeFields
+}
+
+type envelopeFields Envelope
+
+// NewEnvelope copies the first 85 bytes of the transaction, which must already
+// have its nonce set, into an unsigned envelope. Shorter input is rejected
+// with ErrInvalidLength.
+func NewEnvelope(t Transaction) (*Envelope, error) {
+	if len(t) < payloadLength {
+		return nil, ErrInvalidLength
+	}
+
+	tx := Transaction(append([]byte{}, t[0:payloadLength]...))
+
+	return &Envelope{Payload: tx, EnvelopeDetails: envelopeDetails(tx)}, nil
+}
+
+func envelopeDetails(tx Transaction) (e EnvelopeDetails) {
+	e.Type = txTypeName(tx.Version())
+	e.Sender = tx.Sender().Bech32()
+	e.Nonce = tx.Nonce()
+
+	switch tx.Version() {
+	case Genesis, Basic:
+		e.Recipient, e.Amount = tx.Recipient().Bech32(), tx.Amount()
+	case CreateStructure, UpdateStructure:
+		e.Prefix, e.Profit, e.Fee, e.Name = tx.Prefix(), tx.Profit(), tx.Fee(), tx.Name()
+	default:
+		e.Recipient = tx.Recipient().Bech32()
+	}
+
+	return e
+}
+
+// Verify checks that the readable fields match the payload.
+func (e *Envelope) Verify() error {
+	if len(e.Payload) != payloadLength {
+		return ErrInvalidEnvelope
+	}
+
+	if len(e.Signature) != 0 && len(e.Signature) != ed25519.SignatureSize {
+		return ErrInvalidEnvelope
+	}
+
+	if e.EnvelopeDetails != envelopeDetails(e.Payload) {
+		return ErrEnvelopeMismatch
+	}
+
+	return nil
+}
+
+// SignEnvelope verifies the envelope and signs its payload. The signer must
+// own the sender address.
+func SignEnvelope(e *Envelope, s Signer) error {
+	if err := e.Verify(); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(s.Address(), Transaction(e.Payload).Sender()) {
+		return ErrInvalidSender
+	}
+
+	e.Signature = s.Sign(e.Payload)
+
+	return nil
+}
+
+// Transaction combines the payload and the signature into a transaction and
+// verifies it.
+func (e *Envelope) Transaction() (Transaction, error) {
+	if err := e.Verify(); err != nil {
+		return nil, err
+	}
+
+	if len(e.Signature) == 0 {
+		return nil, ErrEnvelopeUnsigned
+	}
+
+	tx := make(Transaction, TxLength)
+	copy(tx, e.Payload)
+	setTxSignature(tx, e.Signature)
+
+	if err := VerifyTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// MarshalJSON ...
+func (e *Envelope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(envelopeJSON{
+		Version:        envelopeVersion,
+		Payload:        hex.EncodeToString(e.Payload),
+		Signature:      hex.EncodeToString(e.Signature),
+		envelopeFields: (*envelopeFields)(e),
+	})
+}
+
+// UnmarshalJSON ...
+func (e *Envelope) UnmarshalJSON(b []byte) error {
+	var (
+		v   envelopeJSON
+		err error
+	)
+
+	v.envelopeFields = (*envelopeFields)(e)
+
+	if err = json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	if v.Version != envelopeVersion {
+		return ErrInvalidEnvelope
+	}
+
+	if e.Payload, err = hex.DecodeString(v.Payload); err != nil {
+		return ErrInvalidEnvelope
+	}
+
+	if e.Signature, err = hex.DecodeString(v.Signature); err != nil {
+		return ErrInvalidEnvelope
+	}
+
+	if len(e.Signature) == 0 {
+		e.Signature = nil
+	}
+
+	return nil
+}
+
+func txTypeName(v uint8) string {
+	if int(v) < len(txTypeNames) {
+		return txTypeNames[v]
+	}
+
+	return "unknown"
+}
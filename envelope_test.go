@@ -0,0 +1,185 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func newEnvelopeTx() (libumi.Transaction, libumi.Signer) {
+	_, sec, adr, _ := libumi.GenerateKey(rand.Reader, "umi")
+	s, _ := libumi.NewSigner(sec, "umi")
+	_, _, rcp, _ := libumi.GenerateKey(rand.Reader, "aaa")
+
+	tx := libumi.NewTransaction().SetSender(adr).SetRecipient(rcp).SetAmount(12_34).SetNonce(7)
+
+	return tx, s
+}
+
+func TestEnvelope(t *testing.T) {
+	tx, s := newEnvelopeTx()
+
+	// Online side.
+	e, err := libumi.NewEnvelope(tx)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	unsigned, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if !bytes.Contains(unsigned, []byte(`"amount":"12.34"`)) {
+		t.Fatalf("Expected: amount 12.34, got: %s", unsigned)
+	}
+
+	// Offline side.
+	var env libumi.Envelope
+	if err := json.Unmarshal(unsigned, &env); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if err := libumi.SignEnvelope(&env, s); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	signed, _ := json.Marshal(&env)
+
+	// Online side.
+	var back libumi.Envelope
+	if err := json.Unmarshal(signed, &back); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	act, err := back.Transaction()
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if !bytes.Equal(act[0:85], tx[0:85]) || act.Nonce() != 7 {
+		t.Fatalf("Expected: %x, got: %x", tx[0:85], act[0:85])
+	}
+}
+
+func TestEnvelopeStructure(t *testing.T) {
+	_, sec, adr, _ := libumi.GenerateKey(rand.Reader, "umi")
+	s, _ := libumi.NewSigner(sec, "umi")
+
+	tx := libumi.NewTransaction().SetVersion(libumi.CreateStructure).SetSender(adr).
+		SetPrefix("aaa").SetProfit(2_50).SetFee(10_00).SetName("Test")
+
+	env, err := libumi.NewEnvelope(tx)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if env.Type != "create_structure" || env.Name != "Test" || env.Recipient != "" {
+		t.Fatalf("Expected: create_structure Test, got: %s %s %s", env.Type, env.Name, env.Recipient)
+	}
+
+	_ = libumi.SignEnvelope(env, s)
+
+	if _, err := env.Transaction(); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+}
+
+func TestNewEnvelopeInvalidLength(t *testing.T) {
+	for _, n := range []int{0, 40, 84} {
+		if _, err := libumi.NewEnvelope(make(libumi.Transaction, n)); !errors.Is(err, libumi.ErrInvalidLength) {
+			t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidLength, err)
+		}
+	}
+
+	if _, err := libumi.NewEnvelope(make(libumi.Transaction, 85)); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+}
+
+func TestEnvelopeTampered(t *testing.T) {
+	tx, s := newEnvelopeTx()
+	_, other, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	wrong, _ := libumi.NewSigner(other, "umi")
+
+	e, _ := libumi.NewEnvelope(tx)
+	b, _ := json.Marshal(e)
+
+	// Metadata edited to show a different amount.
+	var env libumi.Envelope
+	_ = json.Unmarshal([]byte(strings.Replace(string(b), "12.34", "1.34", 1)), &env)
+
+	if err := libumi.SignEnvelope(&env, s); !errors.Is(err, libumi.ErrEnvelopeMismatch) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrEnvelopeMismatch, err)
+	}
+
+	// Signed by a key that does not own the sender address.
+	e, _ = libumi.NewEnvelope(tx)
+	env = *e
+	if err := libumi.SignEnvelope(&env, wrong); !errors.Is(err, libumi.ErrInvalidSender) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidSender, err)
+	}
+
+	// Not signed at all.
+	if _, err := env.Transaction(); !errors.Is(err, libumi.ErrEnvelopeUnsigned) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrEnvelopeUnsigned, err)
+	}
+
+	// Payload and metadata edited consistently after signing.
+	_ = libumi.SignEnvelope(&env, s)
+	sig := env.Signature
+	e, _ = libumi.NewEnvelope(libumi.Transaction(append(env.Payload, make([]byte, 65)...)).SetAmount(99_99))
+	env = *e
+	env.Signature = sig
+
+	if _, err := env.Transaction(); !errors.Is(err, libumi.ErrInvalidSignature) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidSignature, err)
+	}
+}
+
+func TestEnvelopeUnmarshalInvalid(t *testing.T) {
+	tests := []string{
+		`{"version":2,"payload":""}`,
+		`{"version":1,"payload":"zz"}`,
+		`{"version":1,"payload":"00","signature":"x"}`,
+	}
+
+	for _, test := range tests {
+		var env libumi.Envelope
+		if err := json.Unmarshal([]byte(test), &env); !errors.Is(err, libumi.ErrInvalidEnvelope) {
+			t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidEnvelope, err)
+		}
+	}
+
+	var env libumi.Envelope
+	_ = json.Unmarshal([]byte(`{"version":1,"payload":"00"}`), &env)
+
+	if err := env.Verify(); !errors.Is(err, libumi.ErrInvalidEnvelope) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidEnvelope, err)
+	}
+}
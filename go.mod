@@ -1,3 +1,5 @@
 module github.com/umitop/libumi
 
 go 1.13
+
+require golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b
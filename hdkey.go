@@ -0,0 +1,136 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// HardenedOffset ...
+const HardenedOffset uint32 = 0x80000000
+
+// ErrInvalidPath ...
+var ErrInvalidPath = errors.New("invalid derivation path")
+
+// HDKey is a SLIP-0010 extended ed25519 key, only hardened derivation is defined.
+type HDKey struct {
+	Key       []byte
+	ChainCode []byte
+}
+
+// NewMasterKey ...
+func NewMasterKey(seed []byte) (*HDKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, ErrInvalidLength
+	}
+
+	return newHDKey([]byte("ed25519 seed"), seed), nil
+}
+
+// DeriveKey derives the key for a path like m/44'/0'/1' from a seed.
+func DeriveKey(seed []byte, path string) (*HDKey, error) {
+	idx, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range idx {
+		if k, err = k.Derive(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+// ParsePath parses a path like m/44'/0'/1', the hardened marker may be
+// written as ', h or H. Non-hardened indexes are rejected.
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, ErrInvalidPath
+	}
+
+	idx := make([]uint32, 0, len(parts)-1)
+
+	for _, p := range parts[1:] {
+		n := len(p) - 1
+		if n < 1 || strings.IndexByte("'hH", p[n]) == -1 || !isDigits(p[:n]) {
+			return nil, ErrInvalidPath
+		}
+
+		i, err := strconv.ParseUint(p[:n], 10, 31)
+		if err != nil {
+			return nil, ErrInvalidPath
+		}
+
+		idx = append(idx, uint32(i)+HardenedOffset)
+	}
+
+	return idx, nil
+}
+
+// Derive ...
+func (k *HDKey) Derive(index uint32) (*HDKey, error) {
+	if index < HardenedOffset {
+		return nil, ErrInvalidPath
+	}
+
+	data := make([]byte, 1+ed25519.SeedSize+4)
+	copy(data[1:], k.Key)
+	binary.BigEndian.PutUint32(data[1+ed25519.SeedSize:], index)
+
+	return newHDKey(k.ChainCode, data), nil
+}
+
+// PrivateKey ...
+func (k *HDKey) PrivateKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(k.Key)
+}
+
+// PublicKey ...
+func (k *HDKey) PublicKey() ed25519.PublicKey {
+	return k.PrivateKey().Public().(ed25519.PublicKey)
+}
+
+// Address ...
+func (k *HDKey) Address(prefix string) (Address, error) {
+	return AddressFromPublicKey(k.PublicKey(), prefix)
+}
+
+func newHDKey(key, data []byte) *HDKey {
+	h := hmac.New(sha512.New, key)
+	h.Write(data)
+	i := h.Sum(nil)
+
+	return &HDKey{Key: i[:32], ChainCode: i[32:]}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Errors.
+var (
+	ErrInvalidKey       = errors.New("invalid key")
+	ErrSecretMarshaling = errors.New("private key must not be marshaled")
+)
+
+const redacted = "PrivateKey(redacted)"
+
+// PrivateKey holds ed25519 secret key material. Its contents are never
+// printed by fmt and it refuses to be marshaled. It is accepted by SignTransaction
+// and SignBlock as is.
+type PrivateKey []byte
+
+// NewPrivateKey ...
+func NewPrivateKey(sec ed25519.PrivateKey) (PrivateKey, error) {
+	if err := verifyPrivateKey(sec); err != nil {
+		return nil, err
+	}
+
+	return PrivateKey(sec), nil
+}
+
+// PublicKey ...
+func (k PrivateKey) PublicKey() ed25519.PublicKey {
+	return ed25519.PrivateKey(k).Public().(ed25519.PublicKey)
+}
+
+// Address ...
+func (k PrivateKey) Address(prefix string) (Address, error) {
+	return AddressFromPrivateKey(ed25519.PrivateKey(k), prefix)
+}
+
+// Signer ...
+func (k PrivateKey) Signer(prefix string) (Signer, error) {
+	return NewSigner(ed25519.PrivateKey(k), prefix)
+}
+
+// Wipe overwrites the key material with zeros. Copies made earlier are not
+// affected.
+func (k PrivateKey) Wipe() {
+	for i := range k {
+		k[i] = 0
+	}
+}
+
+// String ...
+func (k PrivateKey) String() string {
+	return redacted
+}
+
+// GoString ...
+func (k PrivateKey) GoString() string {
+	return redacted
+}
+
+// Format implements fmt.Formatter so that no verb, including %x and %d,
+// reveals the key.
+func (k PrivateKey) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, redacted)
+}
+
+// MarshalText ...
+func (k PrivateKey) MarshalText() ([]byte, error) {
+	return nil, ErrSecretMarshaling
+}
+
+// MarshalJSON ...
+func (k PrivateKey) MarshalJSON() ([]byte, error) {
+	return nil, ErrSecretMarshaling
+}
+
+// MarshalBinary ...
+func (k PrivateKey) MarshalBinary() ([]byte, error) {
+	return nil, ErrSecretMarshaling
+}
+
+// AddressFromPublicKey ...
+func AddressFromPublicKey(pub ed25519.PublicKey, prefix string) (Address, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	if !bech32VerifyPrefix(prefix) {
+		return nil, ErrInvalidPrefix
+	}
+
+	return NewAddress().SetPrefix(prefix).SetPublicKey(pub), nil
+}
+
+// AddressFromPrivateKey ...
+func AddressFromPrivateKey(sec ed25519.PrivateKey, prefix string) (Address, error) {
+	if err := verifyPrivateKey(sec); err != nil {
+		return nil, err
+	}
+
+	return AddressFromPublicKey(sec.Public().(ed25519.PublicKey), prefix)
+}
+
+// GenerateKey ...
+func GenerateKey(rand io.Reader, prefix string) (ed25519.PublicKey, ed25519.PrivateKey, Address, error) {
+	if !bech32VerifyPrefix(prefix) {
+		return nil, nil, nil, ErrInvalidPrefix
+	}
+
+	pub, sec, err := ed25519.GenerateKey(rand)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	adr, err := AddressFromPublicKey(pub, prefix)
+
+	return pub, sec, adr, err
+}
+
+// verifyPrivateKey checks that the public half of the key matches its seed.
+func verifyPrivateKey(sec ed25519.PrivateKey) error {
+	if len(sec) != ed25519.PrivateKeySize {
+		return ErrInvalidKey
+	}
+
+	if !bytes.Equal(ed25519.NewKeyFromSeed(sec.Seed())[ed25519.SeedSize:], sec[ed25519.SeedSize:]) {
+		return ErrInvalidKey
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func TestGenerateKey(t *testing.T) {
+	pub, sec, adr, err := libumi.GenerateKey(rand.Reader, "aaa")
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if adr.Prefix() != "aaa" || !bytes.Equal(adr.PublicKey(), pub) {
+		t.Fatalf("Expected: aaa %x, got: %s %x", pub, adr.Prefix(), adr.PublicKey())
+	}
+
+	act, err := libumi.AddressFromPrivateKey(sec, "aaa")
+	if err != nil || !bytes.Equal(act, adr) {
+		t.Fatalf("Expected: %x, got: %x (%v)", adr, act, err)
+	}
+
+	if _, _, _, err := libumi.GenerateKey(rand.Reader, "Umi"); !errors.Is(err, libumi.ErrInvalidPrefix) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidPrefix, err)
+	}
+}
+
+func TestAddressFromPublicKey(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		pub    ed25519.PublicKey
+		prefix string
+		exp    error
+	}{
+		{pub, "umi", nil},
+		{pub, "genesis", nil},
+		{pub, "", libumi.ErrInvalidPrefix},
+		{pub, "ab", libumi.ErrInvalidPrefix},
+		{pub, "ab1", libumi.ErrInvalidPrefix},
+		{pub[:31], "umi", libumi.ErrInvalidKey},
+		{nil, "umi", libumi.ErrInvalidKey},
+	}
+
+	for _, test := range tests {
+		adr, err := libumi.AddressFromPublicKey(test.pub, test.prefix)
+		if !errors.Is(err, test.exp) {
+			t.Fatalf("%q Expected: %v, got: %v", test.prefix, test.exp, err)
+		}
+
+		if err == nil && libumi.VerifyAddress(adr) != nil {
+			t.Fatalf("Expected: valid address, got: %v", libumi.VerifyAddress(adr))
+		}
+	}
+}
+
+func TestAddressFromPrivateKeyError(t *testing.T) {
+	_, sec, _ := ed25519.GenerateKey(rand.Reader)
+	bad := append(ed25519.PrivateKey{}, sec...)
+	bad[40] ^= 1
+
+	tests := []ed25519.PrivateKey{nil, sec[:32], bad}
+
+	for _, test := range tests {
+		if _, err := libumi.AddressFromPrivateKey(test, "umi"); !errors.Is(err, libumi.ErrInvalidKey) {
+			t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidKey, err)
+		}
+	}
+}
+
+func TestPrivateKeyRedacted(t *testing.T) {
+	_, sec, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	key, _ := libumi.NewPrivateKey(sec)
+	hex := fmt.Sprintf("%x", []byte(sec))
+
+	wrapped := struct{ Key libumi.PrivateKey }{key}
+	signer, _ := key.Signer("umi")
+
+	for _, f := range []string{"%v", "%+v", "%#v", "%s", "%x", "%X", "%d", "%q"} {
+		for _, v := range []interface{}{key, wrapped, &wrapped} {
+			act := fmt.Sprintf(f, v)
+			if strings.Contains(strings.ToLower(act), hex[:16]) || !strings.Contains(act, "redacted") {
+				t.Fatalf("Expected: redacted, got: %s", act)
+			}
+		}
+
+		act := fmt.Sprintf(f, signer)
+		if strings.Contains(strings.ToLower(act), hex[:16]) || act != "Signer("+signer.Address().Bech32()+")" {
+			t.Fatalf("Expected: Signer(%s), got: %s", signer.Address().Bech32(), act)
+		}
+	}
+}
+
+func TestPrivateKeyMarshal(t *testing.T) {
+	_, sec, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	key, _ := libumi.NewPrivateKey(sec)
+
+	if _, err := json.Marshal(struct{ Key libumi.PrivateKey }{key}); !errors.Is(err, libumi.ErrSecretMarshaling) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrSecretMarshaling, err)
+	}
+}
+
+func TestSigner_Wipe(t *testing.T) {
+	_, sec, adr, _ := libumi.GenerateKey(rand.Reader, "umi")
+	orig := append([]byte(nil), sec...)
+
+	s, _ := libumi.NewSigner(sec, "umi")
+	s.Wipe()
+
+	if !bytes.Equal(sec, orig) {
+		t.Fatalf("Expected: caller's key unchanged")
+	}
+
+	if msg := []byte("msg"); ed25519.Verify(adr.PublicKey(), msg, s.Sign(msg)) {
+		t.Fatalf("Expected: invalid signature after Wipe")
+	}
+}
+
+func TestPrivateKeySign(t *testing.T) {
+	_, sec, adr, _ := libumi.GenerateKey(rand.Reader, "umi")
+	key, _ := libumi.NewPrivateKey(sec)
+
+	tx := libumi.NewTransaction().SetSender(adr).SetRecipient(libumi.NewAddress()).SetValue(1)
+	libumi.SignTransaction(tx, key)
+
+	if err := libumi.VerifyTransaction(tx); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if act, _ := key.Address("umi"); !bytes.Equal(act, adr) {
+		t.Fatalf("Expected: %x, got: %x", adr, act)
+	}
+}
+
+func TestPrivateKeyWipe(t *testing.T) {
+	_, sec, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	key, _ := libumi.NewPrivateKey(append(ed25519.PrivateKey{}, sec...))
+
+	key.Wipe()
+
+	if !bytes.Equal(key, make([]byte, ed25519.PrivateKeySize)) {
+		t.Fatalf("Expected: zeros, got: %x", []byte(key))
+	}
+}
+
+func TestNewPrivateKeyInvalid(t *testing.T) {
+	if _, err := libumi.NewPrivateKey(make([]byte, 10)); !errors.Is(err, libumi.ErrInvalidKey) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidKey, err)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+)
+
+const (
+	pemPrivateKey = "PRIVATE KEY"
+	pemPublicKey  = "PUBLIC KEY"
+	pemOpenSSH    = "OPENSSH PRIVATE KEY"
+	sshMagic      = "openssh-key-v1\x00"
+	sshEd25519    = "ssh-ed25519"
+	sshNone       = "none"
+	sshBlockSize  = 8
+)
+
+// Errors.
+var (
+	ErrUnsupportedKey = errors.New("unsupported key")
+	ErrEncryptedKey   = errors.New("encrypted key")
+)
+
+// ParsePrivateKeyPEM parses an ed25519 key from a PKCS#8 "PRIVATE KEY" or an
+// unencrypted "OPENSSH PRIVATE KEY" PEM block.
+func ParsePrivateKeyPEM(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+
+	switch block.Type {
+	case pemPrivateKey:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+
+		sec, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, ErrUnsupportedKey
+		}
+
+		return sec, nil
+	case pemOpenSSH:
+		return parseOpenSSHPrivateKey(block.Bytes)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// MarshalPrivateKeyPEM encodes the key as a PKCS#8 "PRIVATE KEY" PEM block.
+func MarshalPrivateKeyPEM(sec ed25519.PrivateKey) ([]byte, error) {
+	if err := verifyPrivateKey(sec); err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(sec)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemPrivateKey, Bytes: der}), nil
+}
+
+// MarshalPrivateKeyOpenSSH encodes the key as an unencrypted OpenSSH private key.
+func MarshalPrivateKeyOpenSSH(sec ed25519.PrivateKey, comment string) ([]byte, error) {
+	if err := verifyPrivateKey(sec); err != nil {
+		return nil, err
+	}
+
+	var check [4]byte
+
+	if _, err := rand.Read(check[:]); err != nil {
+		return nil, err
+	}
+
+	pub := sec[ed25519.SeedSize:]
+
+	priv := new(bytes.Buffer)
+	priv.Write(check[:])
+	priv.Write(check[:])
+	sshWriteString(priv, []byte(sshEd25519))
+	sshWriteString(priv, pub)
+	sshWriteString(priv, sec)
+	sshWriteString(priv, []byte(comment))
+
+	for i := byte(1); priv.Len()%sshBlockSize != 0; i++ {
+		priv.WriteByte(i)
+	}
+
+	b := new(bytes.Buffer)
+	b.WriteString(sshMagic)
+	sshWriteString(b, []byte(sshNone))
+	sshWriteString(b, []byte(sshNone))
+	sshWriteString(b, nil)
+	_ = binary.Write(b, binary.BigEndian, uint32(1))
+	sshWriteString(b, sshPublicKeyBlob(pub))
+	sshWriteString(b, priv.Bytes())
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemOpenSSH, Bytes: b.Bytes()}), nil
+}
+
+// ParsePublicKeyPEM parses an ed25519 key from a PKIX "PUBLIC KEY" PEM block.
+func ParsePublicKeyPEM(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemPublicKey {
+		return nil, ErrInvalidKey
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	return pub, nil
+}
+
+// MarshalPublicKeyPEM ...
+func MarshalPublicKeyPEM(pub ed25519.PublicKey) ([]byte, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemPublicKey, Bytes: der}), nil
+}
+
+// ParseAuthorizedKey parses a "ssh-ed25519 AAAA... comment" line.
+func ParseAuthorizedKey(line []byte) (ed25519.PublicKey, string, error) {
+	f := bytes.Fields(line)
+	if len(f) < 2 || string(f[0]) != sshEd25519 {
+		return nil, "", ErrUnsupportedKey
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(string(f[1]))
+	if err != nil {
+		return nil, "", ErrInvalidKey
+	}
+
+	pub, err := sshParsePublicKeyBlob(blob)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return pub, string(bytes.Join(f[2:], []byte(" "))), nil
+}
+
+// MarshalAuthorizedKey ...
+func MarshalAuthorizedKey(pub ed25519.PublicKey, comment string) []byte {
+	s := sshEd25519 + " " + base64.StdEncoding.EncodeToString(sshPublicKeyBlob(pub))
+	if comment != "" {
+		s += " " + comment
+	}
+
+	return []byte(s + "\n")
+}
+
+func parseOpenSSHPrivateKey(b []byte) (ed25519.PrivateKey, error) {
+	if !bytes.HasPrefix(b, []byte(sshMagic)) {
+		return nil, ErrInvalidKey
+	}
+
+	r := &sshReader{b: b[len(sshMagic):]}
+	cipherName, kdfName, _ := r.string(), r.string(), r.string()
+
+	if string(cipherName) != sshNone || string(kdfName) != sshNone {
+		if r.err != nil {
+			return nil, ErrInvalidKey
+		}
+
+		return nil, ErrEncryptedKey
+	}
+
+	if n := r.uint32(); n != 1 {
+		return nil, ErrUnsupportedKey
+	}
+
+	pub, err := sshParsePublicKeyBlob(r.string())
+	if err != nil {
+		return nil, err
+	}
+
+	p := &sshReader{b: r.string()}
+	if r.err != nil || len(p.b)%sshBlockSize != 0 {
+		return nil, ErrInvalidKey
+	}
+
+	if p.uint32() != p.uint32() {
+		return nil, ErrInvalidKey
+	}
+
+	keyType, pub2, sec, _ := p.string(), p.string(), p.string(), p.string()
+
+	if p.err != nil || string(keyType) != sshEd25519 {
+		return nil, ErrInvalidKey
+	}
+
+	if !bytes.Equal(pub, pub2) || len(sec) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	key := ed25519.PrivateKey(append([]byte{}, sec...))
+	if err := verifyPrivateKey(key); err != nil || !bytes.Equal(key[ed25519.SeedSize:], pub) {
+		return nil, ErrInvalidKey
+	}
+
+	return key, nil
+}
+
+func sshPublicKeyBlob(pub []byte) []byte {
+	b := new(bytes.Buffer)
+	sshWriteString(b, []byte(sshEd25519))
+	sshWriteString(b, pub)
+
+	return b.Bytes()
+}
+
+func sshParsePublicKeyBlob(blob []byte) (ed25519.PublicKey, error) {
+	r := &sshReader{b: blob}
+	keyType, pub := r.string(), r.string()
+
+	if r.err != nil || len(r.b) != 0 {
+		return nil, ErrInvalidKey
+	}
+
+	if string(keyType) != sshEd25519 {
+		return nil, ErrUnsupportedKey
+	}
+
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return append(ed25519.PublicKey{}, pub...), nil
+}
+
+func sshWriteString(b *bytes.Buffer, s []byte) {
+	_ = binary.Write(b, binary.BigEndian, uint32(len(s)))
+	b.Write(s)
+}
+
+// sshReader reads SSH wire format values, after the first error all reads
+// return zero values and err is kept.
+type sshReader struct {
+	b   []byte
+	err error
+}
+
+func (r *sshReader) uint32() uint32 {
+	if r.err != nil || len(r.b) < 4 {
+		r.err = ErrInvalidKey
+
+		return 0
+	}
+
+	v := binary.BigEndian.Uint32(r.b)
+	r.b = r.b[4:]
+
+	return v
+}
+
+func (r *sshReader) string() []byte {
+	n := r.uint32()
+	if r.err != nil || uint32(len(r.b)) < n {
+		r.err = ErrInvalidKey
+
+		return nil
+	}
+
+	s := r.b[:n]
+	r.b = r.b[n:]
+
+	return s
+}
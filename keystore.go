@@ -0,0 +1,328 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreVersion = 1
+	keystoreKDF     = "scrypt"
+	keystoreCipher  = "aes-256-gcm"
+	keystoreKeyLen  = 32
+	keystoreSaltLen = 32
+	keystoreExt     = ".json"
+	keystoreMaxN    = 1 << 20
+	keystoreMaxR    = 32
+	keystoreMaxP    = 16
+	keystoreMaxMem  = 1 << 30
+)
+
+// Errors.
+var (
+	ErrInvalidKeystore   = errors.New("invalid keystore")
+	ErrInvalidPassphrase = errors.New("invalid passphrase")
+	ErrKeyNotFound       = errors.New("key not found")
+	ErrKeyExists         = errors.New("key already exists")
+)
+
+// ScryptParams ...
+type ScryptParams struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+// Scrypt parameters. Standard takes about a second and 256 MiB of memory,
+// Light is meant for tests and constrained devices.
+var (
+	StandardScrypt = ScryptParams{N: 1 << 18, R: 8, P: 1}
+	LightScrypt    = ScryptParams{N: 1 << 12, R: 8, P: 6}
+)
+
+type keystoreFile struct {
+	Version int            `json:"version"`
+	Address string         `json:"address"`
+	Crypto  keystoreCrypto `json:"crypto"`
+}
+
+type keystoreCrypto struct {
+	KDF        string         `json:"kdf"`
+	KDFParams  keystoreParams `json:"kdfparams"`
+	Cipher     string         `json:"cipher"`
+	Nonce      string         `json:"nonce"`
+	Ciphertext string         `json:"ciphertext"`
+}
+
+type keystoreParams struct {
+	ScryptParams
+	Salt string `json:"salt"`
+}
+
+// EncryptKey encrypts the key seed with AES-256-GCM under a scrypt derived key.
+// The address is stored in clear and authenticated as additional data.
+func EncryptKey(sec ed25519.PrivateKey, prefix, passphrase string, params ScryptParams) ([]byte, error) {
+	adr, err := AddressFromPrivateKey(sec, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if !params.valid() {
+		return nil, ErrInvalidKeystore
+	}
+
+	salt := make([]byte, keystoreSaltLen)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := keystoreAEAD(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	ks := keystoreFile{
+		Version: keystoreVersion,
+		Address: adr.Bech32(),
+		Crypto: keystoreCrypto{
+			KDF:        keystoreKDF,
+			KDFParams:  keystoreParams{ScryptParams: params, Salt: hex.EncodeToString(salt)},
+			Cipher:     keystoreCipher,
+			Nonce:      hex.EncodeToString(nonce),
+			Ciphertext: hex.EncodeToString(aead.Seal(nil, nonce, sec.Seed(), []byte(adr.Bech32()))),
+		},
+	}
+
+	return json.MarshalIndent(ks, "", "  ")
+}
+
+// DecryptKey ...
+func DecryptKey(data []byte, passphrase string) (ed25519.PrivateKey, Address, error) {
+	ks, adr, err := parseKeystore(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	salt, err1 := hex.DecodeString(ks.Crypto.KDFParams.Salt)
+	nonce, err2 := hex.DecodeString(ks.Crypto.Nonce)
+	ct, err3 := hex.DecodeString(ks.Crypto.Ciphertext)
+
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, nil, ErrInvalidKeystore
+	}
+
+	aead, err := keystoreAEAD(passphrase, salt, ks.Crypto.KDFParams.ScryptParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(nonce) != aead.NonceSize() {
+		return nil, nil, ErrInvalidKeystore
+	}
+
+	seed, err := aead.Open(nil, nonce, ct, []byte(ks.Address))
+	if err != nil {
+		return nil, nil, ErrInvalidPassphrase
+	}
+
+	if len(seed) != ed25519.SeedSize {
+		return nil, nil, ErrInvalidKeystore
+	}
+
+	sec := ed25519.NewKeyFromSeed(seed)
+	PrivateKey(seed).Wipe()
+	if !bytes.Equal(sec[ed25519.SeedSize:], adr.PublicKey()) {
+		return nil, nil, ErrInvalidKeystore
+	}
+
+	return sec, adr, nil
+}
+
+// Keystore keeps encrypted keys in a directory, one file per address.
+type Keystore struct {
+	dir string
+}
+
+// NewKeystore ...
+func NewKeystore(dir string) *Keystore {
+	return &Keystore{dir: dir}
+}
+
+// Store writes the key to a temporary file and renames it into place, so
+// a crash never leaves a partial key file. An existing key file for the same
+// address is never replaced, Store returns ErrKeyExists instead.
+func (k *Keystore) Store(sec ed25519.PrivateKey, prefix, passphrase string, params ScryptParams) (Address, error) {
+	data, err := EncryptKey(sec, prefix, passphrase, params)
+	if err != nil {
+		return nil, err
+	}
+
+	adr, _ := AddressFromPrivateKey(sec, prefix)
+
+	if err = os.MkdirAll(k.dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	if _, err = os.Lstat(k.path(adr)); err == nil {
+		return nil, ErrKeyExists
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return adr, k.writeFile(k.path(adr), data)
+}
+
+// Addresses lists addresses of all key files, other files are skipped.
+func (k *Keystore) Addresses() ([]Address, error) {
+	files, err := ioutil.ReadDir(k.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	adrs := make([]Address, 0, len(files))
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), keystoreExt) {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(k.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if _, adr, err := parseKeystore(data); err == nil {
+			adrs = append(adrs, adr)
+		}
+	}
+
+	return adrs, nil
+}
+
+// Unlock ...
+func (k *Keystore) Unlock(adr Address, passphrase string) (Signer, error) {
+	data, err := ioutil.ReadFile(k.path(adr))
+	if os.IsNotExist(err) {
+		return nil, ErrKeyNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	sec, act, err := DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if act.Key() != adr.Key() {
+		return nil, ErrInvalidKeystore
+	}
+
+	return &keySigner{adr: act, sec: PrivateKey(sec)}, nil
+}
+
+func (k *Keystore) writeFile(path string, data []byte) error {
+	f, err := ioutil.TempFile(k.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func (k *Keystore) path(adr Address) string {
+	return filepath.Join(k.dir, adr.Bech32()+keystoreExt)
+}
+
+func parseKeystore(data []byte) (*keystoreFile, Address, error) {
+	ks := new(keystoreFile)
+
+	if err := json.Unmarshal(data, ks); err != nil {
+		return nil, nil, ErrInvalidKeystore
+	}
+
+	if ks.Version != keystoreVersion || ks.Crypto.KDF != keystoreKDF || ks.Crypto.Cipher != keystoreCipher ||
+		!ks.Crypto.KDFParams.valid() {
+		return nil, nil, ErrInvalidKeystore
+	}
+
+	adr, err := NewAddressFromBech32Mode(ks.Address, Bech32Strict)
+	if err != nil {
+		return nil, nil, ErrInvalidKeystore
+	}
+
+	return ks, adr, nil
+}
+
+// valid limits the cost of a key file, a crafted file must not make
+// DecryptKey allocate gigabytes or run for hours.
+func (p ScryptParams) valid() bool {
+	return p.N > 1 && p.N&(p.N-1) == 0 && p.N <= keystoreMaxN && p.R > 0 && p.R <= keystoreMaxR &&
+		p.P > 0 && p.P <= keystoreMaxP && p.N <= keystoreMaxMem/(128*p.R)
+}
+
+func keystoreAEAD(passphrase string, salt []byte, p ScryptParams) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, p.N, p.R, p.P, keystoreKeyLen)
+	if err != nil {
+		return nil, ErrInvalidKeystore
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+var testScrypt = libumi.ScryptParams{N: 1 << 10, R: 8, P: 1}
+
+func TestDecryptKeyVector(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/keystore.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sec, adr, err := libumi.DecryptKey(data, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	exp := make([]byte, 32)
+	for i := range exp {
+		exp[i] = byte(i + 1)
+	}
+
+	if !bytes.Equal(sec.Seed(), exp) {
+		t.Fatalf("Expected: %x, got: %x", exp, sec.Seed())
+	}
+
+	if adr.Bech32() != "umi10x64vt50ue20jsrckyfw32vt57gplpf6u62ma4lquwgshtgyjejq83veru" {
+		t.Fatalf("Expected: umi10x64vt50ue20jsrckyfw32vt57gplpf6u62ma4lquwgshtgyjejq83veru, got: %s", adr.Bech32())
+	}
+
+	if _, _, err = libumi.DecryptKey(data, "wrong"); !errors.Is(err, libumi.ErrInvalidPassphrase) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidPassphrase, err)
+	}
+
+	// The address is authenticated, swapping it must not decrypt.
+	tampered := strings.Replace(string(data), "umi10x64", "aaa10x64", 1)
+	if _, _, err = libumi.DecryptKey([]byte(tampered), "correct horse battery staple"); err == nil {
+		t.Fatalf("Expected: error, got: nil")
+	}
+
+	// Parameters from the file are capped before any work is done.
+	for _, params := range [][2]string{
+		{`"n": 1024,`, `"n": 8388608,`},
+		{`"p": 1,`, `"p": 8388608,`},
+		{`"r": 8,`, `"r": 64,`},
+		{`"n": 1024,
+      "r": 8,`, `"n": 1048576,
+      "r": 32,`},
+		{`"n": 1024,`, `"n": 1000,`},
+	} {
+		costly := strings.Replace(string(data), params[0], params[1], 1)
+		if costly == string(data) {
+			t.Fatalf("Expected: %s in key file", params[0])
+		}
+
+		if _, _, err = libumi.DecryptKey([]byte(costly), "correct horse battery staple"); !errors.Is(err, libumi.ErrInvalidKeystore) {
+			t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidKeystore, err)
+		}
+	}
+}
+
+func TestEncryptKey(t *testing.T) {
+	_, sec, _ := ed25519.GenerateKey(rand.Reader)
+
+	data, err := libumi.EncryptKey(sec, "aaa", "secret", testScrypt)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	act, adr, err := libumi.DecryptKey(data, "secret")
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if !bytes.Equal(act, sec) || adr.Prefix() != "aaa" {
+		t.Fatalf("Expected: %x, got: %x", sec, act)
+	}
+
+	if _, err = libumi.EncryptKey(sec, "aaa", "secret", libumi.ScryptParams{N: 1000, R: 8, P: 1}); !errors.Is(err, libumi.ErrInvalidKeystore) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidKeystore, err)
+	}
+
+	if _, _, err = libumi.DecryptKey([]byte("{}"), "secret"); !errors.Is(err, libumi.ErrInvalidKeystore) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidKeystore, err)
+	}
+}
+
+func TestKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ks := libumi.NewKeystore(dir)
+	_, sec, _ := ed25519.GenerateKey(rand.Reader)
+
+	adr, err := ks.Store(sec, "umi", "secret", testScrypt)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	before, _ := ioutil.ReadFile(filepath.Join(dir, adr.Bech32()+".json"))
+
+	if _, err = ks.Store(sec, "umi", "other", testScrypt); !errors.Is(err, libumi.ErrKeyExists) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrKeyExists, err)
+	}
+
+	if after, _ := ioutil.ReadFile(filepath.Join(dir, adr.Bech32()+".json")); !bytes.Equal(before, after) {
+		t.Fatalf("Expected: key file unchanged")
+	}
+
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Fatalf("Expected: 1 file, got: %d", len(files))
+	}
+
+	_ = ioutil.WriteFile(filepath.Join(dir, "README.txt"), []byte("not a key"), 0o600)
+	_ = ioutil.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600)
+
+	adrs, err := ks.Addresses()
+	if err != nil || len(adrs) != 1 || adrs[0].Bech32() != adr.Bech32() {
+		t.Fatalf("Expected: [%s], got: %v (%v)", adr.Bech32(), adrs, err)
+	}
+
+	s, err := ks.Unlock(adr, "secret")
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	tx := libumi.NewTransaction().SetSender(s.Address()).SetRecipient(libumi.NewAddress().SetPrefix("aaa"))
+	libumi.SignTransactionWith(tx, s)
+
+	if err = libumi.VerifyTransaction(tx); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	s.Wipe()
+	libumi.SignTransactionWith(tx, s)
+
+	if err = libumi.VerifyTransaction(tx); err == nil {
+		t.Fatalf("Expected: error after Wipe, got: nil")
+	}
+
+	if _, err = ks.Unlock(adr, "wrong"); !errors.Is(err, libumi.ErrInvalidPassphrase) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidPassphrase, err)
+	}
+
+	if _, err = ks.Unlock(libumi.NewAddress(), "secret"); !errors.Is(err, libumi.ErrKeyNotFound) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrKeyNotFound, err)
+	}
+}
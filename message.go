@@ -0,0 +1,63 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// messagePrefix separates signed messages from transactions and blocks. A
+// signed message payload is 53 bytes long and starts with 0x19, which is not a
+// valid transaction or block version, while transactions sign 85 bytes and
+// blocks sign 103 bytes.
+const messagePrefix = "\x19UMI Signed Message:\n"
+
+// SignMessage signs an arbitrary message and returns the signature encoded
+// as unpadded URL-safe base64.
+func SignMessage(s Signer, msg []byte) string {
+	return base64.RawURLEncoding.EncodeToString(s.Sign(messagePayload(msg)))
+}
+
+// VerifyMessage checks that the message was signed by the owner of the address.
+func VerifyMessage(adr Address, msg []byte, sig string) error {
+	if VerifyAddress(adr) != nil {
+		return ErrInvalidAddress
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || len(b) != ed25519.SignatureSize {
+		return ErrInvalidSignature
+	}
+
+	if !ed25519.Verify(adr.PublicKey(), messagePayload(msg), b) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func messagePayload(msg []byte) []byte {
+	h := sha256.Sum256(msg)
+
+	return append([]byte(messagePrefix), h[:]...)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func TestSignMessage(t *testing.T) {
+	_, sec, adr, _ := libumi.GenerateKey(rand.Reader, "umi")
+	s, _ := libumi.NewSigner(sec, "umi")
+	msg := []byte("login nonce 42")
+
+	sig := libumi.SignMessage(s, msg)
+	if len(sig) != 86 {
+		t.Fatalf("Expected: %d, got: %d", 86, len(sig))
+	}
+
+	if err := libumi.VerifyMessage(adr, msg, sig); err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+}
+
+func TestVerifyMessageInvalid(t *testing.T) {
+	_, sec, adr, _ := libumi.GenerateKey(rand.Reader, "umi")
+	_, _, other, _ := libumi.GenerateKey(rand.Reader, "umi")
+	s, _ := libumi.NewSigner(sec, "umi")
+	msg := []byte("login nonce 42")
+	sig := libumi.SignMessage(s, msg)
+
+	tampered := "A" + sig[1:]
+	if sig[0] == 'A' {
+		tampered = "B" + sig[1:]
+	}
+
+	tests := map[string]struct {
+		adr libumi.Address
+		msg string
+		sig string
+		err error
+	}{
+		"message":   {adr, "login nonce 43", sig, libumi.ErrInvalidSignature},
+		"address":   {other, string(msg), sig, libumi.ErrInvalidSignature},
+		"signature": {adr, string(msg), tampered, libumi.ErrInvalidSignature},
+		"encoding":  {adr, string(msg), sig + "==", libumi.ErrInvalidSignature},
+		"short":     {adr, string(msg), sig[:40], libumi.ErrInvalidSignature},
+		"version":   {libumi.NewAddress().SetVersion(0xffff), string(msg), sig, libumi.ErrInvalidAddress},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := libumi.VerifyMessage(test.adr, []byte(test.msg), test.sig); !errors.Is(err, test.err) {
+				t.Fatalf("Expected: %v, got: %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestSignMessageIsNotTransactionSignature(t *testing.T) {
+	_, sec, adr, _ := libumi.GenerateKey(rand.Reader, "umi")
+	s, _ := libumi.NewSigner(sec, "umi")
+
+	tx := libumi.NewTransaction().SetSender(adr).SetRecipient(libumi.NewAddress()).SetValue(1)
+	sig, _ := base64.RawURLEncoding.DecodeString(libumi.SignMessage(s, tx[0:85]))
+	copy(tx[85:85+ed25519.SignatureSize], sig)
+
+	if err := libumi.VerifyTransaction(tx); !errors.Is(err, libumi.ErrInvalidSignature) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidSignature, err)
+	}
+}
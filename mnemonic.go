@@ -0,0 +1,275 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	mnemonicIter     = 2048
+	mnemonicSeedLen  = 64
+	mnemonicMaxHints = 5
+)
+
+// Errors.
+var (
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+	ErrUnknownWord     = errors.New("unknown word")
+	ErrInvalidChecksum = errors.New("invalid checksum")
+)
+
+var (
+	wordList  = strings.Fields(englishWords)
+	wordIndex = makeWordIndex(wordList)
+)
+
+// MnemonicError ...
+type MnemonicError struct {
+	Err         error
+	Index       int      // position of the unknown word
+	Word        string   // the unknown word
+	Suggestions []string // closest words from the wordlist
+}
+
+// Error ...
+func (e *MnemonicError) Error() string {
+	s := ErrInvalidMnemonic.Error() + ": " + e.Err.Error()
+
+	if e.Word != "" {
+		s += " \"" + e.Word + "\""
+	}
+
+	if len(e.Suggestions) > 0 {
+		s += ", did you mean " + strings.Join(e.Suggestions, ", ") + "?"
+	}
+
+	return s
+}
+
+// Unwrap ...
+func (e *MnemonicError) Unwrap() error {
+	return e.Err
+}
+
+// Is ...
+func (e *MnemonicError) Is(target error) bool {
+	return target == ErrInvalidMnemonic
+}
+
+// NewMnemonic generates a BIP-39 mnemonic of 12, 15, 18, 21 or 24 words.
+func NewMnemonic(rand io.Reader, words int) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", ErrInvalidLength
+	}
+
+	entropy := make([]byte, words/3*4)
+
+	if _, err := io.ReadFull(rand, entropy); err != nil {
+		return "", err
+	}
+
+	return NewMnemonicFromEntropy(entropy)
+}
+
+// NewMnemonicFromEntropy ...
+func NewMnemonicFromEntropy(entropy []byte) (string, error) {
+	n := len(entropy)
+	if n < 16 || n > 32 || n%4 != 0 {
+		return "", ErrInvalidLength
+	}
+
+	h := sha256.Sum256(entropy)
+	b := append(append(make([]byte, 0, n+1), entropy...), h[0])
+	words := make([]string, (n*8+n/4)/11)
+
+	for i := range words {
+		words[i] = wordList[getBits11(b, i)]
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy ...
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, &MnemonicError{Err: ErrInvalidLength}
+	}
+
+	b := make([]byte, (len(words)*11+7)/8)
+
+	for i, w := range words {
+		idx, ok := wordIndex[w]
+		if !ok {
+			return nil, &MnemonicError{Err: ErrUnknownWord, Index: i, Word: w, Suggestions: suggestWords(w)}
+		}
+
+		setBits11(b, i, idx)
+	}
+
+	n := len(words) / 3 * 4
+	h := sha256.Sum256(b[:n])
+	cs := uint(n / 4)
+
+	if (b[n]^h[0])>>(8-cs) != 0 {
+		return nil, &MnemonicError{Err: ErrInvalidChecksum}
+	}
+
+	return b[:n], nil
+}
+
+// VerifyMnemonic ...
+func VerifyMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+
+	return err
+}
+
+// MnemonicToSeed returns the 64-byte BIP-39 seed. The passphrase is used as is,
+// non-ASCII passphrases must be normalized to NFKD by the caller.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := VerifyMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	m := strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+
+	return pbkdf2.Key([]byte(m), []byte("mnemonic"+passphrase), mnemonicIter, mnemonicSeedLen, sha512.New), nil
+}
+
+// PrivateKeyFromSeed returns the ed25519 key for a seed. Seeds which are not
+// exactly ed25519.SeedSize bytes long, like BIP-39 seeds, are hashed with SHA-256.
+func PrivateKeyFromSeed(seed []byte) ed25519.PrivateKey {
+	if len(seed) != ed25519.SeedSize {
+		h := sha256.Sum256(seed)
+		seed = h[:]
+	}
+
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+// AddressFromSeed ...
+func AddressFromSeed(seed []byte, prefix string) (Address, error) {
+	return AddressFromPrivateKey(PrivateKeyFromSeed(seed), prefix)
+}
+
+func makeWordIndex(words []string) map[string]int {
+	m := make(map[string]int, len(words))
+
+	for i, w := range words {
+		m[w] = i
+	}
+
+	return m
+}
+
+func getBits11(b []byte, i int) (v int) {
+	for j := i * 11; j < i*11+11; j++ {
+		v = v<<1 | int(b[j/8]>>(7-uint(j%8))&1)
+	}
+
+	return v
+}
+
+func setBits11(b []byte, i int, v int) {
+	for j := 0; j < 11; j++ {
+		if v>>(10-uint(j))&1 == 1 {
+			k := i*11 + j
+			b[k/8] |= 1 << (7 - uint(k%8))
+		}
+	}
+}
+
+// suggestWords returns the closest words within an edit distance of two,
+// a word sharing the unique four-letter prefix of w is the closest.
+func suggestWords(w string) []string {
+	type hint struct {
+		word string
+		dist int
+	}
+
+	var hints []hint
+
+	for _, c := range wordList {
+		d := editDistance(w, c)
+
+		if len(w) >= 4 && strings.HasPrefix(c, w[:4]) {
+			d = 0
+		}
+
+		if d <= 2 {
+			hints = append(hints, hint{c, d})
+		}
+	}
+
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].dist < hints[j].dist
+	})
+
+	res := make([]string, 0, mnemonicMaxHints)
+
+	for i := 0; i < len(hints) && i < mnemonicMaxHints && hints[i].dist == hints[0].dist; i++ {
+		res = append(res, hints[i].word)
+	}
+
+	return res
+}
+
+// editDistance is the optimal string alignment distance, a transposition of
+// two adjacent letters counts as one edit.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(min(d[i-1][j]+1, d[i][j-1]+1), d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/umitop/libumi/qrcode"
+)
+
+const paperWalletWords = 24
+
+// PaperWallet is a key pair for printing. The secret is a 24-word BIP-39
+// mnemonic, the key is derived with MnemonicToSeed without a passphrase and
+// PrivateKeyFromSeed, so RecoverPaperWallet or any BIP-39 tool followed by
+// PrivateKeyFromSeed restores it.
+type PaperWallet struct {
+	Address  Address
+	Mnemonic string
+}
+
+// NewPaperWallet ...
+func NewPaperWallet(rand io.Reader, prefix string) (*PaperWallet, error) {
+	m, err := NewMnemonic(rand, paperWalletWords)
+	if err != nil {
+		return nil, err
+	}
+
+	_, adr, err := RecoverPaperWallet(m, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaperWallet{Address: adr, Mnemonic: m}, nil
+}
+
+// RecoverPaperWallet restores the key and address from the mnemonic.
+func RecoverPaperWallet(mnemonic, prefix string) (PrivateKey, Address, error) {
+	seed, err := MnemonicToSeed(mnemonic, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sec := PrivateKeyFromSeed(seed)
+
+	adr, err := AddressFromPrivateKey(sec, prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return PrivateKey(sec), adr, nil
+}
+
+// PaperWalletChecksum returns eight hex digits of SHA-256 over the address and
+// the normalized mnemonic, so a transcribed wallet can be checked against the
+// printed page.
+func PaperWalletChecksum(mnemonic string, adr Address) string {
+	m := strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+	h := sha256.Sum256([]byte(adr.Bech32() + "\n" + m))
+	s := strings.ToUpper(hex.EncodeToString(h[:4]))
+
+	return s[:4] + "-" + s[4:]
+}
+
+// Checksum ...
+func (w *PaperWallet) Checksum() string {
+	return PaperWalletChecksum(w.Mnemonic, w.Address)
+}
+
+// HTML renders a self-contained printable page with both QR codes embedded as
+// PNG data URIs, so nothing is loaded from the network.
+func (w *PaperWallet) HTML() ([]byte, error) {
+	adrQR, err := w.Address.QRCode(qrcode.M)
+	if err != nil {
+		return nil, err
+	}
+
+	// The phrase stays lower case, which other BIP-39 tools require, so the
+	// code uses byte mode.
+	words := strings.Fields(strings.ToLower(w.Mnemonic))
+
+	secQR, err := qrcode.Encode(strings.Join(words, " "), qrcode.M)
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		Address   string
+		Words     []string
+		Checksum  string
+		AddressQR template.URL
+		SecretQR  template.URL
+	}{
+		Address:  w.Address.Bech32(),
+		Words:    words,
+		Checksum: w.Checksum(),
+	}
+
+	if data.AddressQR, err = pngDataURI(adrQR); err != nil {
+		return nil, err
+	}
+
+	if data.SecretQR, err = pngDataURI(secQR); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	if err := paperWalletTemplate.Execute(&b, data); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func pngDataURI(c *qrcode.Code) (template.URL, error) {
+	b, err := c.PNG(4)
+	if err != nil {
+		return "", err
+	}
+
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(b)), nil //nolint:gosec
+}
+
+var paperWalletTemplate = template.Must(template.New("paper").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>UMI paper wallet {{.Address}}</title>
+<style>
+body { font-family: monospace; margin: 2em; color: #000; background: #fff; }
+section { border: 1px solid #000; padding: 1em; margin-bottom: 2em; page-break-inside: avoid; }
+img { image-rendering: pixelated; }
+ol { columns: 4; }
+.address { word-break: break-all; font-size: 1.2em; }
+</style>
+</head>
+<body>
+<section>
+<h1>Address</h1>
+<img src="{{.AddressQR}}" alt="address QR code">
+<p class="address">{{.Address}}</p>
+</section>
+<section>
+<h1>Secret recovery phrase</h1>
+<p>Anyone with these words can spend the funds. Keep this part private.</p>
+<img src="{{.SecretQR}}" alt="recovery phrase QR code">
+<ol>
+{{range .Words}}<li>{{.}}</li>
+{{end}}</ol>
+</section>
+<p>Checksum: {{.Checksum}}</p>
+</body>
+</html>
+`))
@@ -0,0 +1,114 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"html"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi"
+	"github.com/umitop/libumi/qrcode"
+)
+
+func TestNewPaperWallet(t *testing.T) {
+	w, err := libumi.NewPaperWallet(rand.Reader, "aaa")
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if n := len(strings.Fields(w.Mnemonic)); n != 24 {
+		t.Fatalf("Expected: %d, got: %d", 24, n)
+	}
+
+	// Recovery through the mnemonic path.
+	seed, _ := libumi.MnemonicToSeed(w.Mnemonic, "")
+	if adr, _ := libumi.AddressFromSeed(seed, "aaa"); !bytes.Equal(adr, w.Address) {
+		t.Fatalf("Expected: %s, got: %s", w.Address.Bech32(), adr.Bech32())
+	}
+
+	sec, adr, err := libumi.RecoverPaperWallet(strings.ToUpper(w.Mnemonic), "aaa")
+	if err != nil || !bytes.Equal(adr, w.Address) || !bytes.Equal(sec.PublicKey(), adr.PublicKey()) {
+		t.Fatalf("Expected: %s, got: %s %v", w.Address.Bech32(), adr.Bech32(), err)
+	}
+}
+
+func TestPaperWalletChecksum(t *testing.T) {
+	w, _ := libumi.NewPaperWallet(rand.Reader, "umi")
+	sum := w.Checksum()
+
+	if len(sum) != 9 || sum[4] != '-' {
+		t.Fatalf("Expected: XXXX-XXXX, got: %s", sum)
+	}
+
+	if act := libumi.PaperWalletChecksum("  "+strings.ToUpper(w.Mnemonic)+"\n", w.Address); act != sum {
+		t.Fatalf("Expected: %s, got: %s", sum, act)
+	}
+
+	words := strings.Fields(w.Mnemonic)
+	words[0], words[1] = words[1], words[0]
+
+	if act := libumi.PaperWalletChecksum(strings.Join(words, " "), w.Address); act == sum && words[0] != words[1] {
+		t.Fatalf("Expected: other than %s, got: %s", sum, act)
+	}
+}
+
+func TestPaperWallet_HTML(t *testing.T) {
+	w, _ := libumi.NewPaperWallet(rand.Reader, "umi")
+
+	b, err := w.HTML()
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	page := string(b)
+
+	for _, exp := range []string{w.Address.Bech32(), w.Checksum(), `src="data:image/png;base64,`} {
+		if !strings.Contains(page, exp) {
+			t.Fatalf("Expected: %s, got: %s", exp, page)
+		}
+	}
+
+	if n := strings.Count(page, "<li>"); n != 24 {
+		t.Fatalf("Expected: %d, got: %d", 24, n)
+	}
+
+	if n := strings.Count(page, "data:image/png"); n != 2 || strings.Contains(page, "http:") || strings.Contains(page, "https:") {
+		t.Fatalf("Expected: 2 embedded images and no links, got: %d", n)
+	}
+
+	// The secret code holds the lower case phrase as BIP-39 tools expect it.
+	c, _ := qrcode.Encode(w.Mnemonic, qrcode.M)
+	png, _ := c.PNG(4)
+
+	if !strings.Contains(html.UnescapeString(page), base64.StdEncoding.EncodeToString(png)) {
+		t.Fatalf("Expected: QR code of the lower case mnemonic")
+	}
+
+	w.Mnemonic = strings.ToUpper(w.Mnemonic)
+
+	if b, _ = w.HTML(); !strings.Contains(html.UnescapeString(string(b)), base64.StdEncoding.EncodeToString(png)) {
+		t.Fatalf("Expected: QR code of the lower case mnemonic")
+	}
+}
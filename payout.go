@@ -0,0 +1,239 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Errors.
+var (
+	ErrInvalidRow         = errors.New("invalid row")
+	ErrDuplicateRecipient = errors.New("duplicate recipient")
+	ErrEmptyPayout        = errors.New("empty payout")
+	ErrNonceOverflow      = errors.New("nonce overflow")
+)
+
+// Payment ...
+type Payment struct {
+	Row       int
+	Recipient Address
+	Amount    Amount
+}
+
+// Payout is a validated batch of payments from a single sender.
+type Payout struct {
+	Sender   Address
+	Payments []Payment
+	Total    Amount
+}
+
+// RowError ...
+type RowError struct {
+	Row int
+	Err error
+}
+
+// Error ...
+func (e *RowError) Error() string {
+	return "row " + strconv.Itoa(e.Row) + ": " + e.Err.Error()
+}
+
+// Unwrap ...
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// PayoutError lists every invalid row of a batch.
+type PayoutError struct {
+	Rows []*RowError
+}
+
+// Error ...
+func (e *PayoutError) Error() string {
+	s := make([]string, len(e.Rows))
+
+	for i, r := range e.Rows {
+		s[i] = r.Error()
+	}
+
+	return strconv.Itoa(len(e.Rows)) + " invalid rows: " + strings.Join(s, "; ")
+}
+
+// Unwrap returns the first row error.
+func (e *PayoutError) Unwrap() error {
+	return e.Rows[0]
+}
+
+// ParsePayoutCSV reads "address,amount" rows with an optional header. The whole
+// batch is rejected with a *PayoutError if any row has an invalid address or
+// amount, pays the same address twice, or would not pass VerifyTransaction once
+// signed by the sender.
+func ParsePayoutCSV(r io.Reader, sender Address) (*Payout, error) {
+	if VerifyAddress(sender) != nil {
+		return nil, ErrInvalidSender
+	}
+
+	if err := assert(NewTransaction().SetSender(sender), senderPrefixValidAndNot(genesis)); err != nil {
+		return nil, err
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) > 0 && len(records[0]) > 0 && strings.EqualFold(records[0][0], "address") {
+		records[0] = nil
+	}
+
+	var (
+		p    = Payout{Sender: append(Address{}, sender...)}
+		errs []*RowError
+		seen = make(map[AddressKey]int)
+	)
+
+	for i, rec := range records {
+		if rec == nil {
+			continue
+		}
+
+		pay, err := parsePayment(i+1, rec, sender)
+
+		if err == nil {
+			if _, ok := seen[pay.Recipient.Key()]; ok {
+				err = ErrDuplicateRecipient
+			}
+		}
+
+		if err == nil {
+			seen[pay.Recipient.Key()] = pay.Row
+			p.Payments = append(p.Payments, pay)
+			p.Total, err = p.Total.Add(pay.Amount)
+		}
+
+		if err != nil {
+			errs = append(errs, &RowError{Row: i + 1, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &PayoutError{Rows: errs}
+	}
+
+	if len(p.Payments) == 0 {
+		return nil, ErrEmptyPayout
+	}
+
+	return &p, nil
+}
+
+func parsePayment(row int, rec []string, sender Address) (Payment, error) {
+	if len(rec) != 2 {
+		return Payment{}, ErrInvalidRow
+	}
+
+	adr, err := NewAddressFromBech32(strings.TrimSpace(rec[0]))
+	if err != nil {
+		return Payment{}, err
+	}
+
+	// The same checks VerifyTransaction applies to a Basic transaction,
+	// apart from the signature.
+	tx := NewTransaction().SetSender(sender).SetRecipient(adr)
+	if err = assert(tx, senderAndRecipientNotEqual, recipientPrefixValidAndNot(genesis)); err != nil {
+		return Payment{}, err
+	}
+
+	amt, err := ParseAmount(strings.TrimSpace(rec[1]))
+	if err != nil {
+		return Payment{}, err
+	}
+
+	if amt == 0 {
+		return Payment{}, ErrInvalidAmount
+	}
+
+	return Payment{Row: row, Recipient: adr, Amount: amt}, nil
+}
+
+// Sign builds and signs a Basic transaction for every payment. Payment i gets
+// nonce base+i, so the nonces are unique within the batch; the caller chooses
+// base so that they are unique across batches as well. The signer must own
+// the sender address of the payout.
+func (p *Payout) Sign(s Signer, base uint64) ([]Transaction, error) {
+	if len(p.Payments) == 0 {
+		return nil, ErrEmptyPayout
+	}
+
+	if s.Address().Key() != p.Sender.Key() {
+		return nil, ErrInvalidSender
+	}
+
+	if base > math.MaxUint64-uint64(len(p.Payments)-1) {
+		return nil, ErrNonceOverflow
+	}
+
+	txs := make([]Transaction, len(p.Payments))
+
+	for i, pay := range p.Payments {
+		tx := NewTransaction().SetSender(s.Address()).SetRecipient(pay.Recipient).SetAmount(pay.Amount)
+		tx.SetNonce(base + uint64(i))
+		setTxSignature(tx, s.Sign(tx[0:85]))
+
+		if err := VerifyTransaction(tx); err != nil {
+			return nil, &RowError{Row: pay.Row, Err: err}
+		}
+
+		txs[i] = tx
+	}
+
+	return txs, nil
+}
+
+// Report returns a dry-run summary of the batch.
+func (p *Payout) Report() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Sender:   %s\nPayments: %d\nTotal:    %s UMI\n\n", p.Sender.Bech32(), len(p.Payments), p.Total)
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Row\tRecipient\tAmount\t")
+
+	for _, pay := range p.Payments {
+		fmt.Fprintf(w, "%d\t%s\t%s\t\n", pay.Row, pay.Recipient.Bech32(), pay.Amount)
+	}
+
+	_ = w.Flush()
+
+	return b.String()
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"crypto/rand"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func payoutAddresses(n int) []string {
+	s := make([]string, n)
+
+	for i := range s {
+		_, _, adr, _ := libumi.GenerateKey(rand.Reader, "aaa")
+		s[i] = adr.Bech32()
+	}
+
+	return s
+}
+
+func TestPayout(t *testing.T) {
+	adr := payoutAddresses(3)
+	csv := "address,amount\n" + adr[0] + ",1.50\n" + adr[1] + ", 2\n" + adr[2] + ",0.01\n"
+
+	_, sec, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	s, _ := libumi.NewSigner(sec, "umi")
+
+	p, err := libumi.ParsePayoutCSV(strings.NewReader(csv), s.Address())
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if len(p.Payments) != 3 || p.Total != 3_51 || p.Payments[2].Row != 4 {
+		t.Fatalf("Expected: 3 3.51 4, got: %d %s %d", len(p.Payments), p.Total, p.Payments[2].Row)
+	}
+
+	txs, err := p.Sign(s, 1000)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	for i, tx := range txs {
+		if err := libumi.VerifyTransaction(tx); err != nil {
+			t.Fatalf("Expected: nil, got: %v", err)
+		}
+
+		if tx.Nonce() != uint64(1000+i) || tx.Amount() != p.Payments[i].Amount || tx.Recipient().Bech32() != adr[i] {
+			t.Fatalf("Expected: %d %s %s, got: %d %s %s", 1000+i, p.Payments[i].Amount, adr[i],
+				tx.Nonce(), tx.Amount(), tx.Recipient().Bech32())
+		}
+	}
+
+	if _, err := p.Sign(s, math.MaxUint64-1); !errors.Is(err, libumi.ErrNonceOverflow) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrNonceOverflow, err)
+	}
+
+	_, other, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	wrong, _ := libumi.NewSigner(other, "umi")
+
+	if _, err := p.Sign(wrong, 1000); !errors.Is(err, libumi.ErrInvalidSender) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidSender, err)
+	}
+
+	if rep := p.Report(); !strings.Contains(rep, "Sender:   "+s.Address().Bech32()) ||
+		!strings.Contains(rep, "Total:    3.51 UMI") || !strings.Contains(rep, adr[2]) {
+		t.Fatalf("Expected: report, got: %s", rep)
+	}
+}
+
+func TestPayoutInvalid(t *testing.T) {
+	adr := payoutAddresses(2)
+	_, _, sender, _ := libumi.GenerateKey(rand.Reader, "umi")
+	genesis := libumi.NewAddress().SetVersion(0)
+
+	csv := adr[0] + ",1\n" +
+		adr[1][:10] + ",1\n" +
+		adr[1] + ",1.001\n" +
+		adr[0] + ",2\n" +
+		adr[1] + ",0\n" +
+		adr[1] + "\n" +
+		sender.Bech32() + ",1\n" +
+		genesis.Bech32() + ",1\n"
+
+	_, err := libumi.ParsePayoutCSV(strings.NewReader(csv), sender)
+
+	var perr *libumi.PayoutError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Expected: *PayoutError, got: %v", err)
+	}
+
+	exp := []error{
+		libumi.ErrInvalidAddress, libumi.ErrInvalidAmount, libumi.ErrDuplicateRecipient,
+		libumi.ErrInvalidAmount, libumi.ErrInvalidRow, libumi.ErrInvalidRecipient, libumi.ErrInvalidRecipient,
+	}
+
+	if len(perr.Rows) != len(exp) {
+		t.Fatalf("Expected: %d, got: %d", len(exp), len(perr.Rows))
+	}
+
+	for i, e := range exp {
+		if perr.Rows[i].Row != i+2 || !errors.Is(perr.Rows[i], e) {
+			t.Fatalf("Expected: row %d %v, got: %v", i+2, e, perr.Rows[i])
+		}
+	}
+
+	if !errors.Is(err, libumi.ErrInvalidAddress) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidAddress, err)
+	}
+}
+
+func TestPayoutEmpty(t *testing.T) {
+	_, _, sender, _ := libumi.GenerateKey(rand.Reader, "umi")
+
+	if _, err := libumi.ParsePayoutCSV(strings.NewReader("address,amount\n"), sender); !errors.Is(err, libumi.ErrEmptyPayout) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrEmptyPayout, err)
+	}
+}
+
+func TestPayoutInvalidSender(t *testing.T) {
+	csv := payoutAddresses(1)[0] + ",1\n"
+
+	for _, sender := range []libumi.Address{nil, libumi.NewAddress().SetVersion(0), libumi.NewAddress().SetVersion(1)} {
+		if _, err := libumi.ParsePayoutCSV(strings.NewReader(csv), sender); !errors.Is(err, libumi.ErrInvalidSender) {
+			t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidSender, err)
+		}
+	}
+}
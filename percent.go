@@ -0,0 +1,108 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+// Percent limits in basis points.
+const (
+	MinProfitPercent = 1_00
+	MaxProfitPercent = 5_00
+	MinFeePercent    = 0
+	MaxFeePercent    = 20_00
+)
+
+const percentScale = 100_00
+
+// ErrInvalidPercent ...
+var ErrInvalidPercent = errors.New("invalid percent")
+
+// Percent is a value in basis points, 1% = 100.
+type Percent uint16
+
+// ParsePercent parses strings like "2.5%" or "2.5", at most two decimal places.
+func ParsePercent(s string) (Percent, error) {
+	a, err := ParseAmount(strings.TrimSuffix(s, "%"))
+	if err != nil || a > math.MaxUint16 {
+		return 0, ErrInvalidPercent
+	}
+
+	return Percent(a), nil
+}
+
+// String ...
+func (p Percent) String() string {
+	s := strings.TrimRight(Amount(p).String(), "0")
+
+	return strings.TrimSuffix(s, ".") + "%"
+}
+
+// VerifyProfit ...
+func (p Percent) VerifyProfit() error {
+	if notBetween(uint16(p), MinProfitPercent, MaxProfitPercent) {
+		return ErrInvalidProfitPercent
+	}
+
+	return nil
+}
+
+// VerifyFee ...
+func (p Percent) VerifyFee() error {
+	if notBetween(uint16(p), MinFeePercent, MaxFeePercent) {
+		return ErrInvalidFeePercent
+	}
+
+	return nil
+}
+
+// Of returns p percent of a, the result is rounded down.
+func (p Percent) Of(a Amount) (Amount, error) {
+	hi, lo := bits.Mul64(uint64(a), uint64(p))
+	if hi >= percentScale {
+		return 0, ErrAmountOverflow
+	}
+
+	q, _ := bits.Div64(hi, lo, percentScale)
+
+	return Amount(q), nil
+}
+
+// MarshalText ...
+func (p Percent) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText ...
+func (p *Percent) UnmarshalText(b []byte) error {
+	v, err := ParsePercent(string(b))
+	if err != nil {
+		return err
+	}
+
+	*p = v
+
+	return nil
+}
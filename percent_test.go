@@ -0,0 +1,123 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func TestParsePercent(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp libumi.Percent
+		str string
+	}{
+		{"2.5%", 2_50, "2.5%"},
+		{"5%", 5_00, "5%"},
+		{"0.01%", 1, "0.01%"},
+		{"0%", 0, "0%"},
+		{"20", 20_00, "20%"},
+		{"655.35%", math.MaxUint16, "655.35%"},
+	}
+
+	for _, test := range tests {
+		act, err := libumi.ParsePercent(test.in)
+		if err != nil {
+			t.Fatalf("%s Expected: nil, got: %v", test.in, err)
+		}
+
+		if act != test.exp {
+			t.Fatalf("Expected: %d, got: %d", test.exp, act)
+		}
+
+		if act.String() != test.str {
+			t.Fatalf("Expected: %s, got: %s", test.str, act.String())
+		}
+	}
+}
+
+func TestParsePercentError(t *testing.T) {
+	tests := []string{"", "%", "2.555%", "-1%", "655.36%", "1%%", "5 %"}
+
+	for _, test := range tests {
+		_, err := libumi.ParsePercent(test)
+		if !errors.Is(err, libumi.ErrInvalidPercent) {
+			t.Fatalf("%q Expected: %v, got: %v", test, libumi.ErrInvalidPercent, err)
+		}
+	}
+}
+
+func TestPercent_Verify(t *testing.T) {
+	tests := []struct {
+		p      libumi.Percent
+		profit error
+		fee    error
+	}{
+		{0, libumi.ErrInvalidProfitPercent, nil},
+		{1_00, nil, nil},
+		{5_00, nil, nil},
+		{5_01, libumi.ErrInvalidProfitPercent, nil},
+		{20_00, libumi.ErrInvalidProfitPercent, nil},
+		{20_01, libumi.ErrInvalidProfitPercent, libumi.ErrInvalidFeePercent},
+	}
+
+	for _, test := range tests {
+		if err := test.p.VerifyProfit(); !errors.Is(err, test.profit) {
+			t.Fatalf("%v Expected: %v, got: %v", test.p, test.profit, err)
+		}
+
+		if err := test.p.VerifyFee(); !errors.Is(err, test.fee) {
+			t.Fatalf("%v Expected: %v, got: %v", test.p, test.fee, err)
+		}
+	}
+}
+
+func TestPercent_Of(t *testing.T) {
+	tests := []struct {
+		p   libumi.Percent
+		a   libumi.Amount
+		exp libumi.Amount
+	}{
+		{2_50, 100_00, 2_50},
+		{2_50, 1_99, 4},
+		{1, 99_99, 0},
+		{100_00, math.MaxUint64, math.MaxUint64},
+	}
+
+	for _, test := range tests {
+		act, err := test.p.Of(test.a)
+		if err != nil {
+			t.Fatalf("Expected: nil, got: %v", err)
+		}
+
+		if act != test.exp {
+			t.Fatalf("%v of %v Expected: %v, got: %v", test.p, test.a, test.exp, act)
+		}
+	}
+
+	if _, err := libumi.Percent(100_01).Of(math.MaxUint64); !errors.Is(err, libumi.ErrAmountOverflow) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrAmountOverflow, err)
+	}
+}
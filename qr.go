@@ -0,0 +1,45 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"strings"
+
+	"github.com/umitop/libumi/qrcode"
+)
+
+// QRCode encodes the address in uppercase, which bech32 allows, so that it
+// fits the compact alphanumeric mode.
+func (a Address) QRCode(level qrcode.Level) (*qrcode.Code, error) {
+	return qrcode.Encode(strings.ToUpper(a.Bech32()), level)
+}
+
+// QRCode encodes the payment request. A request without parameters is
+// uppercased for the alphanumeric mode, others are encoded as bytes.
+func (p PaymentURI) QRCode(level qrcode.Level) (*qrcode.Code, error) {
+	s := p.String()
+
+	if !strings.ContainsRune(s, '?') {
+		s = strings.ToUpper(s)
+	}
+
+	return qrcode.Encode(s, level)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"testing"
+
+	"github.com/umitop/libumi"
+	"github.com/umitop/libumi/qrcode"
+)
+
+func TestAddress_QRCode(t *testing.T) {
+	c, err := libumi.NewAddress().QRCode(qrcode.Q)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	// 62 alphanumeric characters fit version 4 at level Q, as bytes they
+	// would need version 6.
+	if c.Version != 4 {
+		t.Fatalf("Expected: %d, got: %d", 4, c.Version)
+	}
+}
+
+func TestPaymentURI_QRCode(t *testing.T) {
+	tests := []struct {
+		uri     libumi.PaymentURI
+		version int
+	}{
+		{libumi.PaymentURI{Address: libumi.NewAddress()}, 4},
+		{libumi.PaymentURI{Address: libumi.NewAddress(), Amount: 1_00, Label: "Shop"}, 6},
+	}
+
+	for _, test := range tests {
+		c, err := test.uri.QRCode(qrcode.M)
+		if err != nil {
+			t.Fatalf("Expected: nil, got: %v", err)
+		}
+
+		if c.Version != test.version {
+			t.Fatalf("Expected: %d, got: %d", test.version, c.Version)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package qrcode
+
+var formatLevelBits = [4]uint{L: 1, M: 0, Q: 3, H: 2}
+
+func newCode(ver int, level Level, data []byte) *Code {
+	size := ver*4 + 17
+	c := &Code{
+		Size:     size,
+		Version:  ver,
+		Level:    level,
+		modules:  make([]bool, size*size),
+		function: make([]bool, size*size),
+	}
+
+	c.drawFunctionPatterns()
+	c.drawCodewords(data)
+
+	best := -1
+
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormat(mask)
+
+		if p := c.penalty(); best == -1 || p < best {
+			best, c.Mask = p, mask
+		}
+
+		c.applyMask(mask)
+	}
+
+	c.applyMask(c.Mask)
+	c.drawFormat(c.Mask)
+
+	return c
+}
+
+func (c *Code) set(x, y int, dark bool) {
+	c.modules[y*c.Size+x] = dark
+	c.function[y*c.Size+x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.Size; i++ {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	pos := alignmentPositions(c.Version)
+	last := len(pos) - 1
+
+	for i, x := range pos {
+		for j, y := range pos {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format area, it is drawn for every mask.
+	c.drawFormat(0)
+	c.drawVersion()
+}
+
+// drawFinder draws a finder pattern with its separator centered at x, y.
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+
+			if xx >= 0 && xx < c.Size && yy >= 0 && yy < c.Size {
+				d := max(abs(dx), abs(dy))
+				c.set(xx, yy, d != 2 && d != 4)
+			}
+		}
+	}
+}
+
+func (c *Code) drawFormat(mask int) {
+	data := formatLevelBits[c.Level]<<3 | uint(mask)
+	rem := data
+
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>uint(i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i))
+	}
+
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.set(c.Size-1-i, 8, bit(i))
+	}
+
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i))
+	}
+
+	c.set(8, c.Size-8, true)
+}
+
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+
+	rem := uint(c.Version)
+
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1f25
+	}
+
+	bits := uint(c.Version)<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := bits>>uint(i)&1 == 1
+		a, b := c.Size-11+i%3, i/3
+
+		c.set(a, b, dark)
+		c.set(b, a, dark)
+	}
+}
+
+// drawCodewords places the data bits in the two-module wide zigzag columns
+// from the bottom right corner, skipping function modules.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+
+				if !c.function[y*c.Size+x] && i < len(data)*8 {
+					c.modules[y*c.Size+x] = data[i/8]>>uint(7-i%8)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask flips the non-function modules selected by the mask. Applying the
+// same mask twice restores the original modules.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.function[y*c.Size+x] && masked(mask, x, y) {
+				c.modules[y*c.Size+x] = !c.modules[y*c.Size+x]
+			}
+		}
+	}
+}
+
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores the symbol with the four rules of the specification: long
+// runs, 2x2 blocks, finder-like patterns and dark module imbalance.
+func (c *Code) penalty() int {
+	p, dark := 0, 0
+
+	for i := 0; i < c.Size; i++ {
+		p += c.linePenalty(func(j int) bool { return c.modules[i*c.Size+j] })
+		p += c.linePenalty(func(j int) bool { return c.modules[j*c.Size+i] })
+	}
+
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			m := c.modules[y*c.Size+x]
+
+			if m {
+				dark++
+			}
+
+			if x > 0 && y > 0 && m == c.modules[y*c.Size+x-1] && m == c.modules[(y-1)*c.Size+x] &&
+				m == c.modules[(y-1)*c.Size+x-1] {
+				p += 3
+			}
+		}
+	}
+
+	total := c.Size * c.Size
+
+	return p + abs(dark*20-total*10)/total*10
+}
+
+func (c *Code) linePenalty(at func(int) bool) int {
+	p, run := 0, 1
+
+	for j := 1; j <= c.Size; j++ {
+		if j < c.Size && at(j) == at(j-1) {
+			run++
+
+			continue
+		}
+
+		if run >= 5 {
+			p += run - 2
+		}
+
+		run = 1
+	}
+
+	// Dark-light-dark-dark-dark-light-dark with four light modules on one side.
+	pattern := [...]bool{true, false, true, true, true, false, true}
+
+	for j := 0; j+len(pattern) <= c.Size; j++ {
+		match := true
+
+		for k, v := range pattern {
+			if at(j+k) != v {
+				match = false
+
+				break
+			}
+		}
+
+		if match && (c.lightRun(at, j-4, j) || c.lightRun(at, j+7, j+11)) {
+			p += 40
+		}
+	}
+
+	return p
+}
+
+// lightRun reports whether modules from..to-1 are light, treating the area
+// outside the symbol as light.
+func (c *Code) lightRun(at func(int) bool, from, to int) bool {
+	for j := from; j < to; j++ {
+		if j >= 0 && j < c.Size && at(j) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func alignmentPositions(ver int) []int {
+	if ver == 1 {
+		return nil
+	}
+
+	n := ver/7 + 2
+	step := 26
+
+	if ver != 32 {
+		step = (ver*4 + n*2 + 1) / (n*2 - 2) * 2
+	}
+
+	pos := make([]int, n)
+	pos[0] = 6
+
+	for i, p := n-1, ver*4+10; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+
+	return pos
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package qrcode implements a QR code encoder with alphanumeric and byte modes.
+package qrcode
+
+import (
+	"errors"
+	"strings"
+)
+
+// Level is the error correction level.
+type Level uint8
+
+// Error correction levels, recovering about 7%, 15%, 25% and 30% of the code.
+const (
+	L Level = iota
+	M
+	Q
+	H
+)
+
+const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// Errors.
+var (
+	ErrInvalidLevel = errors.New("qrcode: invalid level")
+	ErrTooLong      = errors.New("qrcode: data too long")
+)
+
+// blocks holds the number of error correction blocks and the number of error
+// correction codewords per block for every version and level L, M, Q, H.
+var blocks = [41][4][2]uint8{
+	{},
+	{{1, 7}, {1, 10}, {1, 13}, {1, 17}},
+	{{1, 10}, {1, 16}, {1, 22}, {1, 28}},
+	{{1, 15}, {1, 26}, {2, 18}, {2, 22}},
+	{{1, 20}, {2, 18}, {2, 26}, {4, 16}},
+	{{1, 26}, {2, 24}, {4, 18}, {4, 22}},
+	{{2, 18}, {4, 16}, {4, 24}, {4, 28}},
+	{{2, 20}, {4, 18}, {6, 18}, {5, 26}},
+	{{2, 24}, {4, 22}, {6, 22}, {6, 26}},
+	{{2, 30}, {5, 22}, {8, 20}, {8, 24}},
+	{{4, 18}, {5, 26}, {8, 24}, {8, 28}},
+	{{4, 20}, {5, 30}, {8, 28}, {11, 24}},
+	{{4, 24}, {8, 22}, {10, 26}, {11, 28}},
+	{{4, 26}, {9, 22}, {12, 24}, {16, 22}},
+	{{4, 30}, {9, 24}, {16, 20}, {16, 24}},
+	{{6, 22}, {10, 24}, {12, 30}, {18, 24}},
+	{{6, 24}, {10, 28}, {17, 24}, {16, 30}},
+	{{6, 28}, {11, 28}, {16, 28}, {19, 28}},
+	{{6, 30}, {13, 26}, {18, 28}, {21, 28}},
+	{{7, 28}, {14, 26}, {21, 26}, {25, 26}},
+	{{8, 28}, {16, 26}, {20, 30}, {25, 28}},
+	{{8, 28}, {17, 26}, {23, 28}, {25, 30}},
+	{{9, 28}, {17, 28}, {23, 30}, {34, 24}},
+	{{9, 30}, {18, 28}, {25, 30}, {30, 30}},
+	{{10, 30}, {20, 28}, {27, 30}, {32, 30}},
+	{{12, 26}, {21, 28}, {29, 30}, {35, 30}},
+	{{12, 28}, {23, 28}, {34, 28}, {37, 30}},
+	{{12, 30}, {25, 28}, {34, 30}, {40, 30}},
+	{{13, 30}, {26, 28}, {35, 30}, {42, 30}},
+	{{14, 30}, {28, 28}, {38, 30}, {45, 30}},
+	{{15, 30}, {29, 28}, {40, 30}, {48, 30}},
+	{{16, 30}, {31, 28}, {43, 30}, {51, 30}},
+	{{17, 30}, {33, 28}, {45, 30}, {54, 30}},
+	{{18, 30}, {35, 28}, {48, 30}, {57, 30}},
+	{{19, 30}, {37, 28}, {51, 30}, {60, 30}},
+	{{19, 30}, {38, 28}, {53, 30}, {63, 30}},
+	{{20, 30}, {40, 28}, {56, 30}, {66, 30}},
+	{{21, 30}, {43, 28}, {59, 30}, {70, 30}},
+	{{22, 30}, {45, 28}, {62, 30}, {74, 30}},
+	{{24, 30}, {47, 28}, {65, 30}, {77, 30}},
+	{{25, 30}, {49, 28}, {68, 30}, {81, 30}},
+}
+
+// Code is an encoded QR code symbol without the quiet zone.
+type Code struct {
+	Size    int
+	Version int
+	Level   Level
+	Mask    int
+
+	modules  []bool
+	function []bool
+}
+
+// Encode encodes the text in the smallest version that fits at the given
+// level. Text made only of digits, uppercase letters and " $%*+-./:" uses the
+// alphanumeric mode, anything else is encoded as bytes.
+func Encode(text string, level Level) (*Code, error) {
+	if level > H {
+		return nil, ErrInvalidLevel
+	}
+
+	alnum := isAlphanumeric(text)
+
+	for ver := 1; ver <= 40; ver++ {
+		if bits := dataBits(text, alnum, ver); bits <= dataCodewords(ver, level)*8 {
+			return newCode(ver, level, encodeData(text, alnum, ver, level)), nil
+		}
+	}
+
+	return nil, ErrTooLong
+}
+
+// Black reports whether the module at column x and row y is dark.
+func (c *Code) Black(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.modules[y*c.Size+x]
+}
+
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(alphanumeric, s[i]) == -1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func countBits(alnum bool, ver int) int {
+	switch {
+	case alnum && ver < 10:
+		return 9
+	case alnum && ver < 27:
+		return 11
+	case alnum:
+		return 13
+	case ver < 10:
+		return 8
+	default:
+		return 16
+	}
+}
+
+func dataBits(s string, alnum bool, ver int) int {
+	n := 4 + countBits(alnum, ver)
+
+	if alnum {
+		return n + len(s)/2*11 + len(s)%2*6
+	}
+
+	return n + len(s)*8
+}
+
+// rawCodewords returns the number of codewords, data and error correction,
+// that fit into a symbol of the given version.
+func rawCodewords(ver int) int {
+	n := (16*ver+128)*ver + 64
+
+	if ver >= 2 {
+		align := ver/7 + 2
+		n -= (25*align-10)*align - 55
+
+		if ver >= 7 {
+			n -= 36
+		}
+	}
+
+	return n / 8
+}
+
+func dataCodewords(ver int, level Level) int {
+	b := blocks[ver][level]
+
+	return rawCodewords(ver) - int(b[0])*int(b[1])
+}
+
+type bitBuffer struct {
+	b []byte
+	n int
+}
+
+func (w *bitBuffer) write(v uint, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.b = append(w.b, 0)
+		}
+
+		w.b[w.n/8] |= byte((v>>uint(i))&1) << uint(7-w.n%8)
+		w.n++
+	}
+}
+
+// encodeData returns the interleaved data and error correction codewords.
+func encodeData(s string, alnum bool, ver int, level Level) []byte {
+	var w bitBuffer
+
+	if alnum {
+		w.write(0b0010, 4)
+		w.write(uint(len(s)), countBits(alnum, ver))
+
+		for i := 0; i+1 < len(s); i += 2 {
+			w.write(uint(strings.IndexByte(alphanumeric, s[i])*45+strings.IndexByte(alphanumeric, s[i+1])), 11)
+		}
+
+		if len(s)%2 == 1 {
+			w.write(uint(strings.IndexByte(alphanumeric, s[len(s)-1])), 6)
+		}
+	} else {
+		w.write(0b0100, 4)
+		w.write(uint(len(s)), countBits(alnum, ver))
+
+		for i := 0; i < len(s); i++ {
+			w.write(uint(s[i]), 8)
+		}
+	}
+
+	capacity := dataCodewords(ver, level) * 8
+
+	if t := capacity - w.n; t < 4 {
+		w.write(0, t)
+	} else {
+		w.write(0, 4)
+	}
+
+	w.write(0, (8-w.n%8)%8)
+
+	for pad := uint(0xec); w.n < capacity; pad ^= 0xec ^ 0x11 {
+		w.write(pad, 8)
+	}
+
+	return interleave(w.b, ver, level)
+}
+
+func interleave(data []byte, ver int, level Level) []byte {
+	num, ecLen := int(blocks[ver][level][0]), int(blocks[ver][level][1])
+	raw := rawCodewords(ver)
+	short := num - raw%num
+	shortLen := raw/num - ecLen
+
+	var (
+		dataBlocks = make([][]byte, num)
+		ecBlocks   = make([][]byte, num)
+		gen        = rsGenerator(ecLen)
+	)
+
+	for i, off := 0, 0; i < num; i++ {
+		n := shortLen
+		if i >= short {
+			n++
+		}
+
+		dataBlocks[i] = data[off : off+n]
+		ecBlocks[i] = rsRemainder(dataBlocks[i], gen)
+		off += n
+	}
+
+	out := make([]byte, 0, raw)
+
+	for i := 0; i <= shortLen; i++ {
+		for _, b := range dataBlocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+
+	for i := 0; i < ecLen; i++ {
+		for _, b := range ecBlocks {
+			out = append(out, b[i])
+		}
+	}
+
+	return out
+}
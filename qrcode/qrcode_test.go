@@ -0,0 +1,422 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package qrcode_test
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi/qrcode"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		text    string
+		level   qrcode.Level
+		version int
+	}{
+		{"HELLO WORLD", qrcode.Q, 1},
+		{"UMI1QQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQR5ZCPJ", qrcode.M, 4},
+		{"UMI1QQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQQR5ZCPJ", qrcode.H, 5},
+		{"umi:umi1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqr5zcpj?amount=12.50", qrcode.L, 5},
+		{"umi:umi1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqr5zcpj?amount=12.50&label=Shop", qrcode.Q, 8},
+		{strings.Repeat("Ünïcode ", 24), qrcode.L, 10},
+	}
+
+	for _, test := range tests {
+		c, err := qrcode.Encode(test.text, test.level)
+		if err != nil {
+			t.Fatalf("Expected: nil, got: %v", err)
+		}
+
+		if c.Version != test.version || c.Size != test.version*4+17 {
+			t.Fatalf("Expected: %d, got: %d", test.version, c.Version)
+		}
+
+		b, err := c.PNG(3)
+		if err != nil {
+			t.Fatalf("Expected: nil, got: %v", err)
+		}
+
+		for name, grid := range map[string][][]bool{"png": gridFromPNG(t, b, 3), "svg": gridFromSVG(t, c.SVG(5), 5)} {
+			act, err := decode(grid)
+			if err != nil {
+				t.Fatalf("%s: Expected: nil, got: %v", name, err)
+			}
+
+			if act != test.text {
+				t.Fatalf("%s: Expected: %s, got: %s", name, test.text, act)
+			}
+		}
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	if _, err := qrcode.Encode(strings.Repeat("a", 3000), qrcode.L); !errors.Is(err, qrcode.ErrTooLong) {
+		t.Fatalf("Expected: %v, got: %v", qrcode.ErrTooLong, err)
+	}
+
+	if _, err := qrcode.Encode("a", 4); !errors.Is(err, qrcode.ErrInvalidLevel) {
+		t.Fatalf("Expected: %v, got: %v", qrcode.ErrInvalidLevel, err)
+	}
+}
+
+func gridFromPNG(t *testing.T, b []byte, scale int) [][]bool {
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	size := img.Bounds().Dx()/scale - 2*qrcode.QuietZone
+	grid := make([][]bool, size)
+
+	for y := range grid {
+		grid[y] = make([]bool, size)
+
+		for x := range grid[y] {
+			r, _, _, _ := img.At((x+qrcode.QuietZone)*scale+scale/2, (y+qrcode.QuietZone)*scale+scale/2).RGBA()
+			grid[y][x] = r < 0x8000
+		}
+	}
+
+	return grid
+}
+
+func gridFromSVG(t *testing.T, s string, scale int) [][]bool {
+	m := regexp.MustCompile(`width="(\d+)"`).FindStringSubmatch(s)
+	if m == nil {
+		t.Fatalf("Expected: width, got: %s", s)
+	}
+
+	n, _ := strconv.Atoi(m[1])
+	size := n/scale - 2*qrcode.QuietZone
+	grid := make([][]bool, size)
+
+	for y := range grid {
+		grid[y] = make([]bool, size)
+	}
+
+	for _, run := range regexp.MustCompile(`M(\d+),(\d+)h(\d+)v1h-\d+z`).FindAllStringSubmatch(s, -1) {
+		x, _ := strconv.Atoi(run[1])
+		y, _ := strconv.Atoi(run[2])
+		l, _ := strconv.Atoi(run[3])
+
+		for i := 0; i < l; i++ {
+			grid[y-qrcode.QuietZone][x-qrcode.QuietZone+i] = true
+		}
+	}
+
+	return grid
+}
+
+// The decoder below is written from the specification independently of the
+// encoder and supports versions 1 to 10.
+
+var (
+	testAlignment = [11][]int{
+		nil, nil, {6, 18}, {6, 22}, {6, 26}, {6, 30}, {6, 34}, {6, 22, 38}, {6, 24, 42}, {6, 26, 46}, {6, 28, 50},
+	}
+	// Blocks and error correction codewords per block for levels L, M, Q, H.
+	testBlocks = [11][4][2]int{
+		{},
+		{{1, 7}, {1, 10}, {1, 13}, {1, 17}},
+		{{1, 10}, {1, 16}, {1, 22}, {1, 28}},
+		{{1, 15}, {1, 26}, {2, 18}, {2, 22}},
+		{{1, 20}, {2, 18}, {2, 26}, {4, 16}},
+		{{1, 26}, {2, 24}, {4, 18}, {4, 22}},
+		{{2, 18}, {4, 16}, {4, 24}, {4, 28}},
+		{{2, 20}, {4, 18}, {6, 18}, {5, 26}},
+		{{2, 24}, {4, 22}, {6, 22}, {6, 26}},
+		{{2, 30}, {5, 22}, {8, 20}, {8, 24}},
+		{{4, 18}, {5, 26}, {8, 24}, {8, 28}},
+	}
+	errDecode = errors.New("decode error")
+)
+
+func decode(grid [][]bool) (string, error) {
+	size := len(grid)
+	ver := (size - 17) / 4
+
+	if ver < 1 || ver > 10 || size != ver*4+17 {
+		return "", errDecode
+	}
+
+	level, mask, err := decodeFormat(grid)
+	if err != nil {
+		return "", err
+	}
+
+	codewords := readCodewords(grid, ver, mask)
+
+	data, err := deinterleave(codewords, ver, level)
+	if err != nil {
+		return "", err
+	}
+
+	return parseSegments(data, ver)
+}
+
+func decodeFormat(grid [][]bool) (level, mask int, err error) {
+	var bits int
+
+	at := func(x, y int) int {
+		if grid[y][x] {
+			return 1
+		}
+
+		return 0
+	}
+
+	for i := 0; i <= 5; i++ {
+		bits |= at(8, i) << i
+	}
+
+	bits |= at(8, 7)<<6 | at(8, 8)<<7 | at(7, 8)<<8
+
+	for i := 9; i < 15; i++ {
+		bits |= at(14-i, 8) << i
+	}
+
+	bits ^= 0x5412
+
+	data := bits >> 10
+	rem := data
+
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+
+	if data<<10|rem != bits {
+		return 0, 0, errDecode
+	}
+
+	// Format bits 01, 00, 11, 10 are levels L, M, Q, H.
+	return [4]int{1, 0, 3, 2}[data>>3], data & 7, nil
+}
+
+func isFunction(x, y, size, ver int) bool {
+	switch {
+	case x < 9 && y < 9, x >= size-8 && y < 9, x < 9 && y >= size-8, x == 6, y == 6:
+		return true
+	case ver >= 7 && (x < 6 && y >= size-11 && y < size-8 || y < 6 && x >= size-11 && x < size-8):
+		return true
+	}
+
+	for _, ax := range testAlignment[ver] {
+		for _, ay := range testAlignment[ver] {
+			if ax == 6 && ay == 6 || ax == 6 && ay == size-7 || ax == size-7 && ay == 6 {
+				continue
+			}
+
+			if x >= ax-2 && x <= ax+2 && y >= ay-2 && y <= ay+2 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func readCodewords(grid [][]bool, ver, mask int) []byte {
+	size := len(grid)
+	masks := []func(x, y int) bool{
+		func(x, y int) bool { return (x+y)%2 == 0 },
+		func(x, y int) bool { return y%2 == 0 },
+		func(x, y int) bool { return x%3 == 0 },
+		func(x, y int) bool { return (x+y)%3 == 0 },
+		func(x, y int) bool { return (x/3+y/2)%2 == 0 },
+		func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+		func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+		func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+	}
+
+	var (
+		out []byte
+		n   int
+	)
+
+	upward := true
+
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+
+		for i := 0; i < size; i++ {
+			y := i
+			if upward {
+				y = size - 1 - i
+			}
+
+			for x := right; x >= right-1; x-- {
+				if isFunction(x, y, size, ver) {
+					continue
+				}
+
+				if n%8 == 0 {
+					out = append(out, 0)
+				}
+
+				if grid[y][x] != masks[mask](x, y) {
+					out[n/8] |= 0x80 >> uint(n%8)
+				}
+
+				n++
+			}
+		}
+
+		upward = !upward
+	}
+
+	return out[:n/8]
+}
+
+func deinterleave(codewords []byte, ver, level int) ([]byte, error) {
+	num, ecLen := testBlocks[ver][level][0], testBlocks[ver][level][1]
+	short := num - len(codewords)%num
+	shortLen := len(codewords)/num - ecLen
+	blocks := make([][]byte, num)
+	i := 0
+
+	for j := 0; j <= shortLen; j++ {
+		for b := range blocks {
+			if j < shortLen || b >= short {
+				blocks[b] = append(blocks[b], codewords[i])
+				i++
+			}
+		}
+	}
+
+	var data []byte
+
+	for b := range blocks {
+		data = append(data, blocks[b]...)
+
+		for j := 0; j < ecLen; j++ {
+			blocks[b] = append(blocks[b], codewords[i+j*num+b])
+		}
+
+		if !syndromesZero(blocks[b], ecLen) {
+			return nil, errDecode
+		}
+	}
+
+	return data, nil
+}
+
+// syndromesZero evaluates the block polynomial at 2^0..2^(n-1) in GF(256).
+func syndromesZero(block []byte, n int) bool {
+	mul := func(a, b int) int {
+		p := 0
+
+		for ; b > 0; b >>= 1 {
+			if b&1 == 1 {
+				p ^= a
+			}
+
+			a <<= 1
+			if a&0x100 != 0 {
+				a ^= 0x11d
+			}
+		}
+
+		return p
+	}
+
+	root := 1
+
+	for i := 0; i < n; i++ {
+		s := 0
+
+		for _, c := range block {
+			s = mul(s, root) ^ int(c)
+		}
+
+		if s != 0 {
+			return false
+		}
+
+		root = mul(root, 2)
+	}
+
+	return true
+}
+
+func parseSegments(data []byte, ver int) (string, error) {
+	pos := 0
+	read := func(n int) int {
+		v := 0
+
+		for i := 0; i < n; i++ {
+			if pos/8 < len(data) && data[pos/8]&(0x80>>uint(pos%8)) != 0 {
+				v |= 1 << uint(n-1-i)
+			}
+
+			pos++
+		}
+
+		return v
+	}
+
+	var s strings.Builder
+
+	for pos+4 <= len(data)*8 {
+		switch read(4) {
+		case 0:
+			return s.String(), nil
+		case 2:
+			bits := 9
+			if ver >= 10 {
+				bits = 11
+			}
+
+			for n := read(bits); n > 0; n -= 2 {
+				if n == 1 {
+					s.WriteByte("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"[read(6)])
+
+					break
+				}
+
+				v := read(11)
+				s.WriteByte("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"[v/45])
+				s.WriteByte("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"[v%45])
+			}
+		case 4:
+			bits := 8
+			if ver >= 10 {
+				bits = 16
+			}
+
+			for n := read(bits); n > 0; n-- {
+				s.WriteByte(byte(read(8)))
+			}
+		default:
+			return "", errDecode
+		}
+	}
+
+	return s.String(), nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+// QuietZone is the width in modules of the light border around the symbol.
+const QuietZone = 4
+
+// Image renders the code with scale pixels per module and a quiet zone.
+func (c *Code) Image(scale int) *image.Paletted {
+	if scale < 1 {
+		scale = 1
+	}
+
+	n := (c.Size + 2*QuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, n, n), color.Palette{color.White, color.Black})
+
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if c.Black(x/scale-QuietZone, y/scale-QuietZone) {
+				img.Pix[y*img.Stride+x] = 1
+			}
+		}
+	}
+
+	return img
+}
+
+// PNG ...
+func (c *Code) PNG(scale int) ([]byte, error) {
+	var b bytes.Buffer
+
+	if err := png.Encode(&b, c.Image(scale)); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// SVG renders the code as a single path of horizontal runs in module units,
+// scaled to scale pixels per module.
+func (c *Code) SVG(scale int) string {
+	if scale < 1 {
+		scale = 1
+	}
+
+	n := strconv.Itoa(c.Size + 2*QuietZone)
+	px := strconv.Itoa((c.Size + 2*QuietZone) * scale)
+
+	var b strings.Builder
+
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + px + `" height="` + px +
+		`" viewBox="0 0 ` + n + ` ` + n + `" shape-rendering="crispEdges">`)
+	b.WriteString(`<rect width="` + n + `" height="` + n + `" fill="#fff"/><path fill="#000" d="`)
+
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Black(x, y) || c.Black(x-1, y) {
+				continue
+			}
+
+			run := 1
+			for c.Black(x+run, y) {
+				run++
+			}
+
+			b.WriteString("M" + strconv.Itoa(x+QuietZone) + "," + strconv.Itoa(y+QuietZone) +
+				"h" + strconv.Itoa(run) + "v1h-" + strconv.Itoa(run) + "z")
+		}
+	}
+
+	b.WriteString(`"/></svg>`)
+
+	return b.String()
+}
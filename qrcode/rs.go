@@ -0,0 +1,91 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package qrcode
+
+// Reed-Solomon error correction over GF(256) with the polynomial
+// x^8+x^4+x^3+x^2+1 and generator 2.
+
+var gfExp, gfLog = gfTables()
+
+func gfTables() (exp [512]byte, log [256]byte) {
+	x := 1
+
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = byte(x), byte(x)
+		log[x] = byte(i)
+
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+
+	return exp, log
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// rsGenerator returns the coefficients, highest degree first and without the
+// leading one, of (x-2^0)(x-2^1)...(x-2^(n-1)).
+func rsGenerator(n int) []byte {
+	g := make([]byte, n)
+	g[n-1] = 1
+
+	root := byte(1)
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			g[j] = gfMul(g[j], root)
+
+			if j+1 < n {
+				g[j] ^= g[j+1]
+			}
+		}
+
+		root = gfMul(root, 2)
+	}
+
+	return g
+}
+
+// rsRemainder returns the error correction codewords of the data.
+func rsRemainder(data, gen []byte) []byte {
+	r := make([]byte, len(gen))
+
+	for _, b := range data {
+		f := b ^ r[0]
+
+		copy(r, r[1:])
+		r[len(r)-1] = 0
+
+		for i := range r {
+			r[i] ^= gfMul(gen[i], f)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RenderTransaction returns a canonical multi-line description of the
+// transaction. The output is plain ASCII and does not depend on the locale:
+// amounts always use a dot and two decimals, the name is quoted with non-ASCII
+// characters escaped, and every warning from TransactionWarnings is appended.
+// A transaction of the wrong length is rendered as a single warning.
+func RenderTransaction(t Transaction) string {
+	var b strings.Builder
+
+	line := func(k, v string) {
+		b.WriteString(k)
+		b.WriteString(":")
+		b.WriteString(strings.Repeat(" ", 12-len(k)))
+		b.WriteString(v)
+		b.WriteString("\n")
+	}
+
+	if len(t) != TxLength {
+		line("Warning", TransactionWarnings(t)[0])
+
+		return b.String()
+	}
+
+	line("Type", txTypeName(t.Version()))
+	line("Sender", t.Sender().Bech32())
+
+	switch t.Version() {
+	case Genesis, Basic:
+		line("Recipient", t.Recipient().Bech32())
+		line("Amount", t.Amount().String()+" UMI")
+	case CreateStructure, UpdateStructure:
+		line("Structure", t.Prefix())
+		line("Profit", t.Profit().String())
+		line("Fee", t.Fee().String())
+		line("Name", strconv.QuoteToASCII(t.Name()))
+	case UpdateProfitAddress:
+		line("Profit to", t.Recipient().Bech32())
+	case UpdateFeeAddress:
+		line("Fee to", t.Recipient().Bech32())
+	case CreateTransitAddress, DeleteTransitAddress:
+		line("Transit", t.Recipient().Bech32())
+	}
+
+	line("Nonce", strconv.FormatUint(t.Nonce(), 10))
+
+	for _, w := range TransactionWarnings(t) {
+		line("Warning", w)
+	}
+
+	return b.String()
+}
+
+// TransactionWarnings lists fields that look suspicious or invalid.
+func TransactionWarnings(t Transaction) (w []string) {
+	if len(t) != TxLength {
+		return append(w, "invalid length, expected "+strconv.Itoa(TxLength)+" bytes, got "+strconv.Itoa(len(t)))
+	}
+
+	if int(t.Version()) >= len(txTypeNames) {
+		return append(w, "unknown transaction type")
+	}
+
+	if VerifyAddress(t.Sender()) != nil {
+		w = append(w, "sender address is invalid")
+	}
+
+	switch t.Version() {
+	case CreateStructure, UpdateStructure:
+		return append(w, structureWarnings(t)...)
+	case Basic:
+		if t.Amount() == 0 {
+			w = append(w, "amount is zero")
+		}
+	}
+
+	if VerifyAddress(t.Recipient()) != nil {
+		w = append(w, "recipient address is invalid")
+	}
+
+	if bytes.Equal(t.Sender(), t.Recipient()) {
+		w = append(w, "recipient is the sender")
+	} else if bytes.Equal(t.Sender().PublicKey(), t.Recipient().PublicKey()) {
+		w = append(w, "recipient has the sender's key")
+	}
+
+	return w
+}
+
+func structureWarnings(t Transaction) (w []string) {
+	if t.Profit().VerifyProfit() != nil {
+		w = append(w, "profit is out of range")
+	}
+
+	if t.Fee().VerifyFee() != nil {
+		w = append(w, "fee is out of range")
+	}
+
+	if t[41] > maxNameLength {
+		w = append(w, "name length exceeds "+strconv.Itoa(maxNameLength)+" bytes")
+	}
+
+	name := t.Name()
+
+	switch {
+	case !utf8.ValidString(name):
+		w = append(w, "name is not valid UTF-8")
+	case strings.IndexFunc(name, func(r rune) bool { return !unicode.IsPrint(r) }) != -1:
+		w = append(w, "name contains non-printable characters")
+	case strings.IndexFunc(name, func(r rune) bool { return r >= utf8.RuneSelf }) != -1:
+		w = append(w, "name contains non-ASCII characters")
+	}
+
+	return w
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func TestRenderTransaction(t *testing.T) {
+	adr := libumi.NewAddress()
+	rcp := libumi.NewAddress().SetPrefix("aaa")
+	rcp[33] = 1
+
+	tx := libumi.NewTransaction().SetSender(adr).SetRecipient(rcp).SetAmount(1_234_567).SetNonce(42)
+
+	exp := "Type:        basic\n" +
+		"Sender:      " + adr.Bech32() + "\n" +
+		"Recipient:   " + rcp.Bech32() + "\n" +
+		"Amount:      12345.67 UMI\n" +
+		"Nonce:       42\n"
+
+	if act := libumi.RenderTransaction(tx); act != exp {
+		t.Fatalf("Expected: %s, got: %s", exp, act)
+	}
+
+	if act := libumi.RenderTransaction(tx[:40]); act != "Warning:     invalid length, expected 150 bytes, got 40\n" {
+		t.Fatalf("Expected: invalid length, got: %s", act)
+	}
+}
+
+func TestRenderTransactionStructure(t *testing.T) {
+	tx := libumi.NewTransaction().SetVersion(libumi.UpdateStructure).SetSender(libumi.NewAddress()).
+		SetPrefix("aaa").SetProfit(2_50).SetFee(10_00).SetName("Fund а")
+
+	act := libumi.RenderTransaction(tx)
+
+	for _, exp := range []string{
+		"Type:        update_structure\n",
+		"Structure:   aaa\n",
+		"Profit:      2.5%\n",
+		"Fee:         10%\n",
+		`Name:        "Fund \u0430"` + "\n",
+		"Warning:     name contains non-ASCII characters\n",
+	} {
+		if !strings.Contains(act, exp) {
+			t.Fatalf("Expected: %s, got: %s", exp, act)
+		}
+	}
+}
+
+func TestTransactionWarnings(t *testing.T) {
+	adr := libumi.NewAddress()
+	rcp := libumi.NewAddress().SetPrefix("aaa")
+	rcp[33] = 1
+
+	tests := map[string]struct {
+		tx  libumi.Transaction
+		exp []string
+	}{
+		"valid": {
+			libumi.NewTransaction().SetSender(adr).SetRecipient(rcp).SetValue(1),
+			nil,
+		},
+		"self": {
+			libumi.NewTransaction().SetSender(adr).SetRecipient(adr).SetValue(1),
+			[]string{"recipient is the sender"},
+		},
+		"same key": {
+			libumi.NewTransaction().SetSender(adr).SetRecipient(libumi.NewAddress().SetPrefix("aaa")),
+			[]string{"amount is zero", "recipient has the sender's key"},
+		},
+		"empty": {
+			nil,
+			[]string{"invalid length, expected 150 bytes, got 0"},
+		},
+		"truncated": {
+			make(libumi.Transaction, 40),
+			[]string{"invalid length, expected 150 bytes, got 40"},
+		},
+		"unknown": {
+			libumi.NewTransaction().SetVersion(255),
+			[]string{"unknown transaction type"},
+		},
+		"name": {
+			libumi.NewTransaction().SetVersion(libumi.CreateStructure).SetSender(adr).
+				SetProfit(1_00).SetName("\xff\xfe"),
+			[]string{"name is not valid UTF-8"},
+		},
+		"long name": {
+			libumi.NewTransaction().SetVersion(libumi.CreateStructure).SetSender(adr).
+				SetProfit(6_00).SetFee(30_00).SetName(strings.Repeat("a", 40)),
+			[]string{"profit is out of range", "fee is out of range", "name length exceeds 35 bytes"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if act := libumi.TransactionWarnings(test.tx); !reflect.DeepEqual(act, test.exp) {
+				t.Fatalf("Expected: %v, got: %v", test.exp, act)
+			}
+		})
+	}
+}
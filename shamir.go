@@ -0,0 +1,243 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/umitop/libumi/bech32"
+)
+
+const (
+	shareHRP      = "umishare"
+	shareVersion  = 1
+	shareChecksum = 4
+	shareHeader   = 3 + AddressLength
+)
+
+// Errors.
+var (
+	ErrInvalidThreshold = errors.New("invalid threshold")
+	ErrInvalidShare     = errors.New("invalid share")
+	ErrNotEnoughShares  = errors.New("not enough shares")
+	ErrShareMismatch    = errors.New("shares do not match")
+)
+
+var gfExp, gfLog = gfTables()
+
+// Share is one part of a key seed split with Shamir's secret sharing over
+// GF(256). It carries the address of the key so that the recovered seed can
+// be verified before use.
+type Share struct {
+	Threshold uint8
+	Index     uint8
+	Address   Address
+	Data      []byte
+}
+
+// SplitKey splits the key seed into n shares, any k of which recover it.
+func SplitKey(sec ed25519.PrivateKey, prefix string, n, k int, rand io.Reader) ([]Share, error) {
+	if k < 2 || k > n || n > 255 {
+		return nil, ErrInvalidThreshold
+	}
+
+	adr, err := AddressFromPrivateKey(sec, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := sec.Seed()
+	shares := make([]Share, n)
+
+	for i := range shares {
+		shares[i] = Share{Threshold: uint8(k), Index: uint8(i + 1), Address: adr, Data: make([]byte, len(seed))}
+	}
+
+	coef := make([]byte, k)
+
+	for b, s := range seed {
+		coef[0] = s
+
+		if _, err := io.ReadFull(rand, coef[1:]); err != nil {
+			return nil, err
+		}
+
+		for i := range shares {
+			shares[i].Data[b] = gfEval(coef, shares[i].Index)
+		}
+	}
+
+	return shares, nil
+}
+
+// CombineShares recovers the key and checks it against the shares address.
+func CombineShares(shares []Share) (ed25519.PrivateKey, error) {
+	if len(shares) == 0 || len(shares) < int(shares[0].Threshold) {
+		return nil, ErrNotEnoughShares
+	}
+
+	if err := verifyShares(shares); err != nil {
+		return nil, err
+	}
+
+	seed := make([]byte, ed25519.SeedSize)
+
+	for i, si := range shares {
+		// Lagrange basis polynomial for share i evaluated at zero.
+		basis := byte(1)
+
+		for j, sj := range shares {
+			if i != j {
+				basis = gfMul(basis, gfDiv(sj.Index, sj.Index^si.Index))
+			}
+		}
+
+		for b := range seed {
+			seed[b] ^= gfMul(si.Data[b], basis)
+		}
+	}
+
+	sec := ed25519.NewKeyFromSeed(seed)
+	if !bytes.Equal(sec[ed25519.SeedSize:], shares[0].Address.PublicKey()) {
+		return nil, ErrShareMismatch
+	}
+
+	return sec, nil
+}
+
+// ParseShare ...
+func ParseShare(s string) (Share, error) {
+	hrp, b, enc, err := bech32.DecodeBytes(s)
+	if err != nil || hrp != shareHRP || enc != bech32.Bech32m {
+		return Share{}, ErrInvalidShare
+	}
+
+	if len(b) != shareHeader+ed25519.SeedSize+shareChecksum || b[0] != shareVersion {
+		return Share{}, ErrInvalidShare
+	}
+
+	body, sum := b[:len(b)-shareChecksum], b[len(b)-shareChecksum:]
+	if h := sha256.Sum256(body); !bytes.Equal(h[:shareChecksum], sum) {
+		return Share{}, ErrInvalidShare
+	}
+
+	sh := Share{
+		Threshold: b[1],
+		Index:     b[2],
+		Address:   NewAddress(),
+		Data:      append([]byte{}, body[shareHeader:]...),
+	}
+
+	copy(sh.Address, b[3:shareHeader])
+
+	if sh.Threshold < 2 || sh.Index == 0 || VerifyAddress(sh.Address) != nil {
+		return Share{}, ErrInvalidShare
+	}
+
+	return sh, nil
+}
+
+// String encodes the share as bech32m with a SHA-256 checksum of its content.
+func (s Share) String() string {
+	b := make([]byte, 0, shareHeader+len(s.Data)+shareChecksum)
+	b = append(b, shareVersion, s.Threshold, s.Index)
+	b = append(b, s.Address...)
+	b = append(b, s.Data...)
+	h := sha256.Sum256(b)
+
+	str, _ := bech32.EncodeBytes(shareHRP, append(b, h[:shareChecksum]...), bech32.Bech32m)
+
+	return str
+}
+
+func verifyShares(shares []Share) error {
+	seen := make(map[uint8]struct{}, len(shares))
+	first := shares[0]
+
+	for _, s := range shares {
+		if s.Index == 0 || len(s.Data) != ed25519.SeedSize || len(s.Address) != AddressLength {
+			return ErrInvalidShare
+		}
+
+		if s.Threshold != first.Threshold || s.Address.Key() != first.Address.Key() {
+			return ErrShareMismatch
+		}
+
+		if _, ok := seen[s.Index]; ok {
+			return ErrShareMismatch
+		}
+
+		seen[s.Index] = struct{}{}
+	}
+
+	return nil
+}
+
+// gfTables builds exponent and logarithm tables of GF(256) with the AES
+// polynomial x^8+x^4+x^3+x+1 and generator 3.
+func gfTables() (exp [510]byte, log [256]byte) {
+	x := byte(1)
+
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = x, x
+		log[x] = byte(i)
+
+		hi := x & 0x80
+		x2 := x << 1
+
+		if hi != 0 {
+			x2 ^= 0x1b
+		}
+
+		x ^= x2
+	}
+
+	return exp, log
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+// gfEval evaluates the polynomial with the given coefficients at x.
+func gfEval(coef []byte, x byte) (y byte) {
+	for i := len(coef) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coef[i]
+	}
+
+	return y
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/umitop/libumi"
+)
+
+func TestSplitKey(t *testing.T) {
+	_, sec, adr, _ := libumi.GenerateKey(rand.Reader, "aaa")
+
+	shares, err := libumi.SplitKey(sec, "aaa", 5, 3, rand.Reader)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	for _, set := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		subset := make([]libumi.Share, 0, len(set))
+
+		for _, i := range set {
+			sh, err := libumi.ParseShare(shares[i].String())
+			if err != nil {
+				t.Fatalf("Expected: nil, got: %v", err)
+			}
+
+			subset = append(subset, sh)
+		}
+
+		act, err := libumi.CombineShares(subset)
+		if err != nil {
+			t.Fatalf("Expected: nil, got: %v", err)
+		}
+
+		if !bytes.Equal(act, sec) {
+			t.Fatalf("Expected: %x, got: %x", sec, act)
+		}
+
+		if !bytes.Equal(subset[0].Address, adr) {
+			t.Fatalf("Expected: %x, got: %x", adr, subset[0].Address)
+		}
+	}
+}
+
+func TestSplitKeyInvalidThreshold(t *testing.T) {
+	_, sec, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+
+	for _, nk := range [][2]int{{3, 1}, {3, 4}, {256, 2}} {
+		if _, err := libumi.SplitKey(sec, "umi", nk[0], nk[1], rand.Reader); !errors.Is(err, libumi.ErrInvalidThreshold) {
+			t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidThreshold, err)
+		}
+	}
+}
+
+func TestCombineSharesNotEnough(t *testing.T) {
+	_, sec, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	shares, _ := libumi.SplitKey(sec, "umi", 3, 3, rand.Reader)
+
+	if _, err := libumi.CombineShares(shares[:2]); !errors.Is(err, libumi.ErrNotEnoughShares) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrNotEnoughShares, err)
+	}
+}
+
+func TestCombineSharesMismatch(t *testing.T) {
+	_, sec1, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	_, sec2, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	a, _ := libumi.SplitKey(sec1, "umi", 3, 2, rand.Reader)
+	b, _ := libumi.SplitKey(sec2, "umi", 3, 2, rand.Reader)
+
+	// Different splits.
+	if _, err := libumi.CombineShares([]libumi.Share{a[0], b[1]}); !errors.Is(err, libumi.ErrShareMismatch) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrShareMismatch, err)
+	}
+
+	// Duplicate share.
+	if _, err := libumi.CombineShares([]libumi.Share{a[0], a[0]}); !errors.Is(err, libumi.ErrShareMismatch) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrShareMismatch, err)
+	}
+
+	// Corrupted data recovers a seed that does not match the address.
+	a[1].Data[0] ^= 1
+	if _, err := libumi.CombineShares(a[:2]); !errors.Is(err, libumi.ErrShareMismatch) {
+		t.Fatalf("Expected: %v, got: %v", libumi.ErrShareMismatch, err)
+	}
+}
+
+func TestParseShareInvalid(t *testing.T) {
+	_, sec, _, _ := libumi.GenerateKey(rand.Reader, "umi")
+	shares, _ := libumi.SplitKey(sec, "umi", 2, 2, rand.Reader)
+	s := shares[0].String()
+
+	typo := "q"
+	if s[20] == 'q' {
+		typo = "p"
+	}
+
+	tests := map[string]string{
+		"empty":    "",
+		"address":  libumi.NewAddress().Bech32(),
+		"truncate": s[:len(s)-1],
+		"typo":     s[:20] + typo + s[21:],
+	}
+
+	for name, str := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := libumi.ParseShare(str); !errors.Is(err, libumi.ErrInvalidShare) {
+				t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidShare, err)
+			}
+		})
+	}
+}
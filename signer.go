@@ -0,0 +1,94 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Signer ...
+type Signer interface {
+	Address() Address
+	Sign(message []byte) []byte
+	// Wipe erases the key material, the signer must not be used afterwards.
+	Wipe()
+}
+
+type keySigner struct {
+	adr Address
+	sec PrivateKey
+}
+
+// NewSigner keeps a copy of the key, Wipe does not affect sec.
+func NewSigner(sec ed25519.PrivateKey, prefix string) (Signer, error) {
+	adr, err := AddressFromPrivateKey(sec, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keySigner{adr: adr, sec: append(PrivateKey(nil), sec...)}, nil
+}
+
+// Address ...
+func (s *keySigner) Address() Address {
+	return s.adr
+}
+
+// Sign ...
+func (s *keySigner) Sign(message []byte) []byte {
+	return ed25519.Sign(ed25519.PrivateKey(s.sec), message)
+}
+
+// Wipe ...
+func (s *keySigner) Wipe() {
+	s.sec.Wipe()
+}
+
+// String ...
+func (s *keySigner) String() string {
+	return "Signer(" + s.adr.Bech32() + ")"
+}
+
+// GoString ...
+func (s *keySigner) GoString() string {
+	return s.String()
+}
+
+// Format implements fmt.Formatter, fmt does not call the methods of
+// PrivateKey on an unexported field and would print the key.
+func (s *keySigner) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, s.String())
+}
+
+// SignTransactionWith ...
+func SignTransactionWith(t []byte, s Signer) {
+	setTxNonce(t, uint64(time.Now().UnixNano()))
+	setTxSignature(t, s.Sign(t[0:85]))
+}
+
+// SignBlockWith ...
+func SignBlockWith(blk []byte, s Signer) {
+	setBlockPublicKey(blk, s.Address().PublicKey())
+	setBlockSignature(blk, s.Sign(blk[0:103]))
+}
@@ -22,6 +22,7 @@ package libumi
 
 import (
 	"crypto/ed25519"
+	"crypto/sha256"
 	"encoding/binary"
 	"time"
 )
@@ -29,6 +30,8 @@ import (
 // TxLength ...
 const TxLength = 150
 
+const maxNameLength = 35
+
 // Types.
 const (
 	Genesis uint8 = iota
@@ -44,6 +47,9 @@ const (
 // Transaction ...
 type Transaction []byte
 
+// TxHash ...
+type TxHash [HashLength]byte
+
 // NewTransaction ...
 func NewTransaction() Transaction {
 	tx := make(Transaction, TxLength)
@@ -52,6 +58,37 @@ func NewTransaction() Transaction {
 	return tx
 }
 
+// NewTxHash returns ErrInvalidLength unless b is exactly HashLength bytes long.
+func NewTxHash(b []byte) (h TxHash, err error) {
+	if len(b) != HashLength {
+		return h, ErrInvalidLength
+	}
+
+	copy(h[:], b)
+
+	return h, nil
+}
+
+// Bytes ...
+func (h TxHash) Bytes() []byte {
+	b := make([]byte, HashLength)
+	copy(b, h[:])
+
+	return b
+}
+
+// Hash ...
+func (t Transaction) Hash() []byte {
+	h := t.Key()
+
+	return h[:]
+}
+
+// Key ...
+func (t Transaction) Key() TxHash {
+	return sha256.Sum256(t)
+}
+
 // Version ...
 func (t Transaction) Version() uint8 {
 	return t[0]
@@ -100,6 +137,16 @@ func (t Transaction) SetValue(n uint64) Transaction {
 	return t
 }
 
+// Amount ...
+func (t Transaction) Amount() Amount {
+	return Amount(t.Value())
+}
+
+// SetAmount ...
+func (t Transaction) SetAmount(a Amount) Transaction {
+	return t.SetValue(uint64(a))
+}
+
 // Prefix ...
 func (t Transaction) Prefix() string {
 	return versionToPrefix(binary.BigEndian.Uint16(t[35:37]))
@@ -124,6 +171,16 @@ func (t Transaction) SetProfitPercent(n uint16) Transaction {
 	return t
 }
 
+// Profit ...
+func (t Transaction) Profit() Percent {
+	return Percent(t.ProfitPercent())
+}
+
+// SetProfit ...
+func (t Transaction) SetProfit(p Percent) Transaction {
+	return t.SetProfitPercent(uint16(p))
+}
+
 // FeePercent ...
 func (t Transaction) FeePercent() uint16 {
 	return binary.BigEndian.Uint16(t[39:41])
@@ -136,9 +193,24 @@ func (t Transaction) SetFeePercent(p uint16) Transaction {
 	return t
 }
 
-// Name ...
+// Fee ...
+func (t Transaction) Fee() Percent {
+	return Percent(t.FeePercent())
+}
+
+// SetFee ...
+func (t Transaction) SetFee(p Percent) Transaction {
+	return t.SetFeePercent(uint16(p))
+}
+
+// Name returns at most 35 bytes even if the length byte is larger.
 func (t Transaction) Name() string {
-	return string(t[42:(42 + t[41])])
+	n := t[41]
+	if n > maxNameLength {
+		n = maxNameLength
+	}
+
+	return string(t[42:(42 + n)])
 }
 
 // SetName ...
@@ -149,6 +221,23 @@ func (t Transaction) SetName(s string) Transaction {
 	return t
 }
 
+// Nonce ...
+func (t Transaction) Nonce() uint64 {
+	return binary.BigEndian.Uint64(t[77:85])
+}
+
+// SetNonce ...
+func (t Transaction) SetNonce(n uint64) Transaction {
+	setTxNonce(t, n)
+
+	return t
+}
+
+// Signature ...
+func (t Transaction) Signature() []byte {
+	return t[85:149]
+}
+
 // SignTransaction ...
 func SignTransaction(t []byte, sec []byte) {
 	setTxNonce(t, uint64(time.Now().UnixNano()))
@@ -175,8 +264,8 @@ func VerifyTransaction(t []byte) error {
 		ifVersionIsCreateOrUpdateStruct(
 			senderPrefixIs(umi),
 			structPrefixValidAndNot(genesis, umi),
-			profitPercentBetween(1_00, 5_00), //nolint:gomnd
-			feePercentBetween(0, 20_00),
+			profitPercentIsValid,
+			feePercentIsValid,
 			nameIsValid,
 		),
 
@@ -21,8 +21,10 @@
 package libumi_test
 
 import (
+	"bytes"
 	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/sha256"
 	"errors"
 	"strings"
 	"testing"
@@ -322,3 +324,24 @@ func TestTransaction_Version(t *testing.T) {
 		t.Fatalf("Expected: %v, got: %v", exp, act)
 	}
 }
+
+func TestTransaction_Key(t *testing.T) {
+	tx := newTx(libumi.Basic, "umi", "aaa")
+	exp := sha256.Sum256(tx)
+	act := tx.Key()
+
+	if act != exp {
+		t.Fatalf("Expected: %x, got: %x", exp, act)
+	}
+
+	h, err := libumi.NewTxHash(tx.Hash())
+	if err != nil || !bytes.Equal(h.Bytes(), exp[:]) {
+		t.Fatalf("Expected: %x, got: %x %v", exp, h, err)
+	}
+
+	for _, n := range []int{0, libumi.HashLength - 1, libumi.HashLength + 1} {
+		if _, err := libumi.NewTxHash(make([]byte, n)); !errors.Is(err, libumi.ErrInvalidLength) {
+			t.Fatalf("Expected: %v, got: %v", libumi.ErrInvalidLength, err)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const uriScheme = "umi:"
+
+// ErrInvalidURI ...
+var ErrInvalidURI = errors.New("invalid payment uri")
+
+// PaymentURI is a payment request of the form
+//
+//	umi:<address>?amount=1.50&label=Shop&message=Order%2042&exp=1600000000
+//
+// Every parameter is optional. Amount zero and a zero Expires mean not set.
+type PaymentURI struct {
+	Address Address
+	Amount  Amount
+	Label   string
+	Message string
+	Expires time.Time
+}
+
+// ParsePaymentURI parses a payment URI. The scheme and the address may be all
+// uppercase, as they are in alphanumeric QR codes. Parameters must be
+// percent-encoded UTF-8 and may appear at most once; '+' is a literal plus.
+// Unknown parameters are ignored unless they start with "req-".
+func ParsePaymentURI(s string) (PaymentURI, error) {
+	var p PaymentURI
+
+	if len(s) < len(uriScheme) || !strings.EqualFold(s[:len(uriScheme)], uriScheme) {
+		return p, ErrInvalidURI
+	}
+
+	rest := s[len(uriScheme):]
+	query := ""
+
+	if i := strings.IndexByte(rest, '?'); i != -1 {
+		rest, query = rest[:i], rest[i+1:]
+	}
+
+	adr, err := NewAddressFromBech32Mode(rest, Bech32Strict)
+	if err != nil {
+		return p, err
+	}
+
+	p.Address = adr
+
+	if query == "" {
+		return p, nil
+	}
+
+	seen := make(map[string]struct{})
+
+	for _, kv := range strings.Split(query, "&") {
+		k, v, err := splitParam(kv)
+		if err != nil {
+			return p, err
+		}
+
+		if _, ok := seen[k]; ok {
+			return p, ErrInvalidURI
+		}
+
+		seen[k] = struct{}{}
+
+		if err := p.setParam(k, v); err != nil {
+			return p, err
+		}
+	}
+
+	return p, nil
+}
+
+// String returns the canonical form: lowercase address, parameters in a fixed
+// order and every byte outside the unreserved set percent-encoded.
+func (p PaymentURI) String() string {
+	var b strings.Builder
+
+	b.WriteString(uriScheme)
+	b.WriteString(p.Address.Bech32())
+
+	sep := byte('?')
+	param := func(k, v string) {
+		b.WriteByte(sep)
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(uriEscape(v))
+
+		sep = '&'
+	}
+
+	if p.Amount != 0 {
+		param("amount", p.Amount.String())
+	}
+
+	if p.Label != "" {
+		param("label", p.Label)
+	}
+
+	if p.Message != "" {
+		param("message", p.Message)
+	}
+
+	if !p.Expires.IsZero() {
+		param("exp", strconv.FormatInt(p.Expires.Unix(), 10))
+	}
+
+	return b.String()
+}
+
+// Expired ...
+func (p PaymentURI) Expired(now time.Time) bool {
+	return !p.Expires.IsZero() && !now.Before(p.Expires)
+}
+
+func (p *PaymentURI) setParam(k, v string) (err error) {
+	switch k {
+	case "amount":
+		p.Amount, err = ParseAmount(v)
+		if err == nil && p.Amount == 0 {
+			err = ErrInvalidAmount
+		}
+	case "label":
+		p.Label = v
+	case "message":
+		p.Message = v
+	case "exp":
+		var n int64
+
+		if !isDigits(v) {
+			return ErrInvalidURI
+		}
+
+		if n, err = strconv.ParseInt(v, 10, 64); err != nil || n == 0 {
+			return ErrInvalidURI
+		}
+
+		p.Expires = time.Unix(n, 0).UTC()
+	default:
+		if strings.HasPrefix(k, "req-") {
+			err = ErrInvalidURI
+		}
+	}
+
+	return err
+}
+
+func splitParam(kv string) (k, v string, err error) {
+	i := strings.IndexByte(kv, '=')
+	if i < 1 {
+		return "", "", ErrInvalidURI
+	}
+
+	if k, err = uriUnescape(kv[:i]); err != nil {
+		return "", "", err
+	}
+
+	v, err = uriUnescape(kv[i+1:])
+
+	return k, v, err
+}
+
+// uriUnescape decodes percent-encoding and rejects raw characters that are not
+// allowed in a query, malformed escapes and invalid UTF-8.
+func uriUnescape(s string) (string, error) {
+	b := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case c == '%':
+			if i+2 >= len(s) {
+				return "", ErrInvalidURI
+			}
+
+			h, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return "", ErrInvalidURI
+			}
+
+			b = append(b, byte(h))
+			i += 2
+		case isUnreserved(c) || strings.IndexByte("!$'()*+,;:@/?", c) != -1:
+			b = append(b, c)
+		default:
+			return "", ErrInvalidURI
+		}
+	}
+
+	if !utf8.Valid(b) {
+		return "", ErrInvalidURI
+	}
+
+	return string(b), nil
+}
+
+func uriEscape(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+		}
+	}
+
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) != -1
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/umitop/libumi"
+)
+
+func TestPaymentURI(t *testing.T) {
+	adr := libumi.NewAddress().SetPrefix("aaa")
+	exp := time.Unix(1600000000, 0).UTC()
+
+	p := libumi.PaymentURI{Address: adr, Amount: 12_50, Label: "Café & Co", Message: "Order #42+1", Expires: exp}
+	s := p.String()
+
+	if want := "umi:" + adr.Bech32() + "?amount=12.50&label=Caf%C3%A9%20%26%20Co&message=Order%20%2342%2B1&exp=1600000000"; s != want {
+		t.Fatalf("Expected: %s, got: %s", want, s)
+	}
+
+	act, err := libumi.ParsePaymentURI(s)
+	if err != nil {
+		t.Fatalf("Expected: nil, got: %v", err)
+	}
+
+	if !bytes.Equal(act.Address, adr) || act.Amount != p.Amount || act.Label != p.Label ||
+		act.Message != p.Message || !act.Expires.Equal(exp) {
+		t.Fatalf("Expected: %+v, got: %+v", p, act)
+	}
+
+	if !act.Expired(exp) || act.Expired(exp.Add(-time.Second)) {
+		t.Fatalf("Expected: expiry at %v", exp)
+	}
+}
+
+func TestParsePaymentURI(t *testing.T) {
+	adr := libumi.NewAddress().Bech32()
+
+	tests := []struct {
+		uri     string
+		amount  libumi.Amount
+		message string
+	}{
+		{"umi:" + adr, 0, ""},
+		{strings.ToUpper("umi:"+adr) + "?amount=1", 1_00, ""},
+		{"UMI:" + adr + "?message=a+b%2fc&unknown=1", 0, "a+b/c"},
+	}
+
+	for _, test := range tests {
+		p, err := libumi.ParsePaymentURI(test.uri)
+		if err != nil {
+			t.Fatalf("Expected: nil, got: %v", err)
+		}
+
+		if p.Address.Bech32() != adr || p.Amount != test.amount || p.Message != test.message {
+			t.Fatalf("Expected: %s %s %s, got: %s %s %s", adr, test.amount, test.message,
+				p.Address.Bech32(), p.Amount, p.Message)
+		}
+	}
+}
+
+func TestParsePaymentURIInvalid(t *testing.T) {
+	adr := libumi.NewAddress().Bech32()
+	mixed := strings.ToUpper(adr[:6]) + adr[6:]
+
+	tests := map[string]struct {
+		uri string
+		err error
+	}{
+		"scheme":    {"bitcoin:" + adr, libumi.ErrInvalidURI},
+		"authority": {"umi://" + adr, libumi.ErrInvalidAddress},
+		"mixed":     {"umi:" + mixed, libumi.ErrInvalidAddress},
+		"amount":    {"umi:" + adr + "?amount=1.001", libumi.ErrInvalidAmount},
+		"zero":      {"umi:" + adr + "?amount=0", libumi.ErrInvalidAmount},
+		"duplicate": {"umi:" + adr + "?label=a&label=b", libumi.ErrInvalidURI},
+		"escape":    {"umi:" + adr + "?label=%zz", libumi.ErrInvalidURI},
+		"truncated": {"umi:" + adr + "?label=a%2", libumi.ErrInvalidURI},
+		"space":     {"umi:" + adr + "?label=a b", libumi.ErrInvalidURI},
+		"utf8":      {"umi:" + adr + "?label=%ff", libumi.ErrInvalidURI},
+		"fragment":  {"umi:" + adr + "?label=a#b", libumi.ErrInvalidURI},
+		"required":  {"umi:" + adr + "?req-fee=1", libumi.ErrInvalidURI},
+		"exp":       {"umi:" + adr + "?exp=-1", libumi.ErrInvalidURI},
+		"empty key": {"umi:" + adr + "?=1", libumi.ErrInvalidURI},
+		"no value":  {"umi:" + adr + "?label", libumi.ErrInvalidURI},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := libumi.ParsePaymentURI(test.uri); !errors.Is(err, test.err) {
+				t.Fatalf("Expected: %v, got: %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/umitop/libumi/bech32"
+)
+
+// ErrInvalidPattern ...
+var ErrInvalidPattern = errors.New("invalid vanity pattern")
+
+// VanityMatch ...
+type VanityMatch uint8
+
+// Vanity match kinds.
+const (
+	VanityPrefix VanityMatch = iota
+	VanitySuffix
+	VanityContains
+)
+
+const vanityBatch = 64
+
+// VanityPattern describes what the data part of an address, the characters
+// after the "1" separator including the checksum, should look like.
+type VanityPattern struct {
+	Match VanityMatch
+	Text  string
+}
+
+// ParseVanityPattern parses "abc*" as a prefix, "*abc" as a suffix and "*abc*"
+// as a substring pattern. Text without asterisks is a prefix.
+func ParseVanityPattern(s string) (VanityPattern, error) {
+	p := VanityPattern{Match: VanityPrefix}
+	head, tail := strings.HasPrefix(s, "*"), strings.HasSuffix(s, "*") && len(s) > 1
+
+	switch {
+	case head && tail:
+		p.Match, p.Text = VanityContains, s[1:len(s)-1]
+	case head:
+		p.Match, p.Text = VanitySuffix, s[1:]
+	case tail:
+		p.Text = s[:len(s)-1]
+	default:
+		p.Text = s
+	}
+
+	p.Text = strings.ToLower(p.Text)
+
+	return p, p.Verify()
+}
+
+// Verify checks that the pattern only uses bech32 alphabet characters and fits
+// into the data part.
+func (p VanityPattern) Verify() error {
+	if p.Match > VanityContains || len(p.Text) == 0 || len(p.Text) > dataLen {
+		return ErrInvalidPattern
+	}
+
+	for i := 0; i < len(p.Text); i++ {
+		if strings.IndexByte(bech32.Charset, p.Text[i]) == -1 {
+			return ErrInvalidPattern
+		}
+	}
+
+	return nil
+}
+
+// Difficulty returns the expected number of keys to generate before a match.
+// The estimate for substring patterns ignores overlapping occurrences.
+func (p VanityPattern) Difficulty() float64 {
+	d := math.Pow(float64(len(bech32.Charset)), float64(len(p.Text)))
+
+	if p.Match == VanityContains {
+		d /= float64(dataLen - len(p.Text) + 1)
+	}
+
+	return math.Max(d, 1)
+}
+
+// String ...
+func (p VanityPattern) String() string {
+	switch p.Match {
+	case VanitySuffix:
+		return "*" + p.Text
+	case VanityContains:
+		return "*" + p.Text + "*"
+	default:
+		return p.Text + "*"
+	}
+}
+
+func (p VanityPattern) match(data string) bool {
+	switch p.Match {
+	case VanitySuffix:
+		return strings.HasSuffix(data, p.Text)
+	case VanityContains:
+		return strings.Contains(data, p.Text)
+	default:
+		return strings.HasPrefix(data, p.Text)
+	}
+}
+
+// VanityResult ...
+type VanityResult struct {
+	Address    Address
+	PrivateKey PrivateKey
+	Attempts   uint64
+	Duration   time.Duration
+}
+
+// Rate returns the number of generated keys per second.
+func (r VanityResult) Rate() float64 {
+	return VanityProgress{Attempts: r.Attempts, Duration: r.Duration}.Rate()
+}
+
+// VanityProgress ...
+type VanityProgress struct {
+	Attempts uint64
+	Duration time.Duration
+}
+
+// Rate returns the number of generated keys per second.
+func (p VanityProgress) Rate() float64 {
+	if p.Duration <= 0 {
+		return 0
+	}
+
+	return float64(p.Attempts) / p.Duration.Seconds()
+}
+
+// FindVanityAddress generates keys on the given number of workers, or on every
+// CPU if workers is not positive, until an address matches the pattern. When
+// ctx is done the attempts made so far are returned along with ctx.Err().
+func FindVanityAddress(ctx context.Context, prefix string, pattern VanityPattern, workers int) (VanityResult, error) {
+	return FindVanityAddressProgress(ctx, prefix, pattern, workers, 0, nil)
+}
+
+// FindVanityAddressProgress is FindVanityAddress that also calls progress
+// every interval while the search runs. Calls come from a single goroutine
+// and none are made after it returns.
+func FindVanityAddressProgress(ctx context.Context, prefix string, pattern VanityPattern, workers int,
+	interval time.Duration, progress func(VanityProgress)) (VanityResult, error) {
+	if !bech32VerifyPrefix(prefix) {
+		return VanityResult{}, ErrInvalidPrefix
+	}
+
+	if err := pattern.Verify(); err != nil {
+		return VanityResult{}, err
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		attempts uint64
+		res      VanityResult
+		err      error
+	)
+
+	start := time.Now()
+	skip := len(prefix) + 1
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			sec, e := vanityWorker(ctx, prefix, skip, pattern, &attempts)
+
+			once.Do(func() {
+				res.PrivateKey, err = sec, e
+				cancel()
+			})
+		}()
+	}
+
+	if progress != nil && interval > 0 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			vanityReport(ctx, start, interval, &attempts, progress)
+		}()
+	}
+
+	wg.Wait()
+
+	res.Attempts = atomic.LoadUint64(&attempts)
+	res.Duration = time.Since(start)
+
+	if err == nil {
+		res.Address, _ = res.PrivateKey.Address(prefix)
+	}
+
+	return res, err
+}
+
+func vanityReport(ctx context.Context, start time.Time, interval time.Duration, attempts *uint64,
+	progress func(VanityProgress)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			progress(VanityProgress{Attempts: atomic.LoadUint64(attempts), Duration: time.Since(start)})
+		}
+	}
+}
+
+// vanityWorker derives keys from consecutive seeds starting at a random one,
+// which is as good as a fresh random seed per key since seeds are hashed.
+func vanityWorker(ctx context.Context, prefix string, skip int, p VanityPattern, attempts *uint64) (PrivateKey, error) {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		for i := 0; i < vanityBatch; i++ {
+			sec := ed25519.NewKeyFromSeed(seed)
+
+			if p.match(bech32Encode(prefix, sec[ed25519.SeedSize:])[skip:]) {
+				atomic.AddUint64(attempts, uint64(i+1))
+
+				return PrivateKey(sec), nil
+			}
+
+			incrementSeed(seed)
+		}
+
+		atomic.AddUint64(attempts, vanityBatch)
+	}
+}
+
+func incrementSeed(seed []byte) {
+	for i := len(seed) - 1; i >= 0; i-- {
+		seed[i]++
+
+		if seed[i] != 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2020 UMI
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package libumi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/umitop/libumi"
+)
+
+func TestFindVanityAddress(t *testing.T) {
+	tests := map[string]func(s, substr string) bool{
+		"qq*":  strings.HasPrefix,
+		"*x8":  strings.HasSuffix,
+		"*3j*": strings.Contains,
+	}
+
+	for s, match := range tests {
+		t.Run(s, func(t *testing.T) {
+			pattern, err := libumi.ParseVanityPattern(s)
+			if err != nil {
+				t.Fatalf("Expected: nil, got: %v", err)
+			}
+
+			res, err := libumi.FindVanityAddress(context.Background(), "aaa", pattern, 2)
+			if err != nil {
+				t.Fatalf("Expected: nil, got: %v", err)
+			}
+
+			data := strings.TrimPrefix(res.Address.Bech32(), "aaa1")
+			text := strings.Trim(s, "*")
+
+			if ok := match(data, text); !ok || res.Attempts == 0 {
+				t.Fatalf("Expected: %s, got: %s after %d", s, data, res.Attempts)
+			}
+
+			if act, _ := res.PrivateKey.Address("aaa"); !bytes.Equal(act, res.Address) {
+				t.Fatalf("Expected: %x, got: %x", res.Address, act)
+			}
+		})
+	}
+}
+
+func TestFindVanityAddressCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pattern, _ := libumi.ParseVanityPattern("qqqqqqqqqq")
+
+	if _, err := libumi.FindVanityAddress(ctx, "umi", pattern, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected: %v, got: %v", context.Canceled, err)
+	}
+}
+
+func TestFindVanityAddressProgress(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	pattern, _ := libumi.ParseVanityPattern("qqqqqqqqqq")
+
+	var (
+		mu      sync.Mutex
+		reports []libumi.VanityProgress
+	)
+
+	res, err := libumi.FindVanityAddressProgress(ctx, "umi", pattern, 2, 20*time.Millisecond, func(p libumi.VanityProgress) {
+		mu.Lock()
+		reports = append(reports, p)
+		mu.Unlock()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected: %v, got: %v", context.DeadlineExceeded, err)
+	}
+
+	mu.Lock()
+	n := len(reports)
+	mu.Unlock()
+
+	if n < 2 {
+		t.Fatalf("Expected: at least 2 reports, got: %d", n)
+	}
+
+	for i := 1; i < n; i++ {
+		if reports[i].Attempts < reports[i-1].Attempts || reports[i].Duration <= reports[i-1].Duration {
+			t.Fatalf("Expected: increasing progress, got: %v then %v", reports[i-1], reports[i])
+		}
+	}
+
+	if last := reports[n-1]; last.Attempts > res.Attempts || last.Rate() <= 0 {
+		t.Fatalf("Expected: at most %d attempts at a positive rate, got: %d at %f", res.Attempts, last.Attempts, last.Rate())
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(reports) != n {
+		t.Fatalf("Expected: no reports after return, got: %d more", len(reports)-n)
+	}
+}
+
+func TestParseVanityPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   libumi.VanityMatch
+		text    string
+		err     error
+	}{
+		{"abc", 0, "", libumi.ErrInvalidPattern},
+		{"qz1*", 0, "", libumi.ErrInvalidPattern},
+		{"*", 0, "", libumi.ErrInvalidPattern},
+		{"", 0, "", libumi.ErrInvalidPattern},
+		{"QZ", libumi.VanityPrefix, "qz", nil},
+		{"qz*", libumi.VanityPrefix, "qz", nil},
+		{"*qz", libumi.VanitySuffix, "qz", nil},
+		{"*qz*", libumi.VanityContains, "qz", nil},
+	}
+
+	for _, test := range tests {
+		p, err := libumi.ParseVanityPattern(test.pattern)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("Expected: %v, got: %v", test.err, err)
+		}
+
+		if err == nil && (p.Match != test.match || p.Text != test.text) {
+			t.Fatalf("Expected: %d %s, got: %d %s", test.match, test.text, p.Match, p.Text)
+		}
+	}
+}
+
+func TestVanityPatternDifficulty(t *testing.T) {
+	p, _ := libumi.ParseVanityPattern("qqq")
+
+	if act := p.Difficulty(); act != 32768 {
+		t.Fatalf("Expected: %v, got: %v", 32768, act)
+	}
+}
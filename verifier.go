@@ -171,7 +171,7 @@ func senderPrefixValidAndNot(v uint16) func([]byte) error {
 }
 
 func senderAndRecipientNotEqual(b []byte) error {
-	if bytes.Equal((Transaction)(b).Recipient(), (Transaction)(b).Sender()) {
+	if (Transaction)(b).Recipient().Key() == (Transaction)(b).Sender().Key() {
 		return ErrInvalidRecipient
 	}
 
@@ -231,9 +231,7 @@ func structPrefixValidAndNot(vs ...uint16) func([]byte) error {
 }
 
 func nameIsValid(b []byte) error {
-	const maxLength = 35
-
-	if b[41] > maxLength {
+	if b[41] > maxNameLength {
 		return ErrInvalidName
 	}
 
@@ -244,26 +242,12 @@ func nameIsValid(b []byte) error {
 	return nil
 }
 
-func feePercentBetween(min, max uint16) func([]byte) error {
-	return func(b []byte) error {
-		p := (Transaction)(b).FeePercent()
-		if notBetween(p, min, max) {
-			return ErrInvalidFeePercent
-		}
-
-		return nil
-	}
+func feePercentIsValid(b []byte) error {
+	return (Transaction)(b).Fee().VerifyFee()
 }
 
-func profitPercentBetween(min, max uint16) func([]byte) error {
-	return func(b []byte) error {
-		p := (Transaction)(b).ProfitPercent()
-		if notBetween(p, min, max) {
-			return ErrInvalidProfitPercent
-		}
-
-		return nil
-	}
+func profitPercentIsValid(b []byte) error {
+	return (Transaction)(b).Profit().VerifyProfit()
 }
 
 func notBetween(v, min, max uint16) bool {
@@ -330,7 +314,7 @@ func merkleRootIsValid(b []byte) error {
 }
 
 func prevBlockHashIsNull(b []byte) error {
-	if !bytes.Equal((Block)(b).PreviousBlockHash(), make([]byte, 32)) {
+	if !bytes.Equal((Block)(b).PreviousBlockHash(), (BlockHash{}).Bytes()) {
 		return ErrInvalidPrevHash
 	}
 
@@ -338,7 +322,7 @@ func prevBlockHashIsNull(b []byte) error {
 }
 
 func prevBlockHashNotNull(b []byte) error {
-	if bytes.Equal((Block)(b).PreviousBlockHash(), make([]byte, 32)) {
+	if bytes.Equal((Block)(b).PreviousBlockHash(), (BlockHash{}).Bytes()) {
 		return ErrInvalidPrevHash
 	}
 